@@ -39,12 +39,58 @@ func RunServer() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Every job, the notification dispatcher and event relay included, runs
+	// on whichever node leads it.
+	elector := worker.NewElector(db, deps.LeaseRepo, cfg.NodeID)
+	defer elector.Close()
+
 	worker.StartAuctionCloser(
 		ctx,
+		elector,
 		cfg.AuctionCloserCron,
 		deps.AuctionService,
-		deps.BidService,
-		deps.Hub,
+	)
+
+	worker.StartAuctionActivator(
+		ctx,
+		elector,
+		cfg.AuctionActivatorCron,
+		deps.AuctionService,
+	)
+
+	worker.StartDutchPriceTicker(
+		ctx,
+		elector,
+		cfg.DutchTickerCron,
+		deps.AuctionService,
+	)
+
+	worker.StartSecondChanceOffers(
+		ctx,
+		elector,
+		cfg.SecondChanceCron,
+		deps.OfferService,
+	)
+
+	worker.StartWatchlistReminders(
+		ctx,
+		elector,
+		cfg.WatchlistCron,
+		deps.WatchService,
+	)
+
+	worker.StartNotificationDispatcher(
+		ctx,
+		elector,
+		cfg.NotifyInterval,
+		deps.NotifyService,
+	)
+
+	worker.StartEventRelay(
+		ctx,
+		elector,
+		cfg.EventRelayInterval,
+		deps.EventRelay,
 	)
 
 	router := routes.SetupRoutes(cfg, deps)
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"rebid/internal/bootstrap"
+	"rebid/internal/config"
+	database "rebid/internal/databases"
+	"rebid/internal/dto"
+	"rebid/pkg"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// runBids fires bids from many goroutines at once, each bidding the minimum
+// over the price it last saw, then checks that the auction row agrees with
+// the bid history: the stored price is the highest bid, its bidder leads, and
+// committed bids only ever go up.
+func runBids(args []string) error {
+	fs := flag.NewFlagSet("bids", flag.ExitOnError)
+	bidders := fs.Int("bidders", 20, "number of concurrent bidders")
+	rounds := fs.Int("rounds", 25, "bids attempted per bidder")
+	keep := fs.Bool("keep", false, "keep the generated auction, item and users")
+	fs.Parse(args)
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	db, err := database.Open(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	deps := bootstrap.BuildDependencies(cfg, db)
+
+	f, err := newAuctionFixtures(ctx, db, *bidders, 10)
+	if f != nil && !*keep {
+		defer f.cleanup(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	var accepted, outbid, rejected atomic.Int64
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for _, userID := range f.userIDs {
+		wg.Add(1)
+		go func(userID uuid.UUID) {
+			defer wg.Done()
+			<-start
+			for i := 0; i < *rounds; i++ {
+				auction, err := deps.AuctionRepo.GetByID(ctx, f.auctionID)
+				if err != nil {
+					rejected.Add(1)
+					continue
+				}
+				_, err = deps.BidService.CreateBid(ctx, &dto.CreateBidRequest{
+					AuctionID: f.auctionID,
+					Amount:    auction.MinNextBid,
+				}, userID)
+
+				var appErr *pkg.AppError
+				switch {
+				case err == nil:
+					accepted.Add(1)
+				case errors.As(err, &appErr) && appErr.StatusCode == http.StatusConflict:
+					outbid.Add(1)
+				default:
+					rejected.Add(1)
+				}
+			}
+		}(userID)
+	}
+
+	began := time.Now()
+	close(start)
+	wg.Wait()
+	elapsed := time.Since(began)
+
+	fmt.Printf("auction %s: %d accepted, %d outbid while submitting, %d rejected in %s\n",
+		f.auctionID, accepted.Load(), outbid.Load(), rejected.Load(), elapsed.Round(time.Millisecond))
+
+	return verifyBidHistory(ctx, f)
+}
+
+func verifyBidHistory(ctx context.Context, f *fixtures) error {
+	var price float64
+	var bidderID *uuid.UUID
+	if err := f.db.QueryRowContext(ctx,
+		`SELECT current_price, current_bidder_id FROM auctions WHERE id = $1`, f.auctionID,
+	).Scan(&price, &bidderID); err != nil {
+		return fmt.Errorf("read auction: %w", err)
+	}
+
+	rows, err := f.db.QueryContext(ctx,
+		`SELECT user_id, amount FROM bids WHERE auction_id = $1 ORDER BY bid_time ASC`, f.auctionID)
+	if err != nil {
+		return fmt.Errorf("read bids: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		count    int
+		last     float64
+		lastUser uuid.UUID
+	)
+	for rows.Next() {
+		var userID uuid.UUID
+		var amount float64
+		if err := rows.Scan(&userID, &amount); err != nil {
+			return fmt.Errorf("scan bid: %w", err)
+		}
+		if count > 0 && amount <= last {
+			return fmt.Errorf("bid #%d of %.2f does not beat the previous %.2f", count+1, amount, last)
+		}
+		count++
+		last = amount
+		lastUser = userID
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("read bids: %w", err)
+	}
+
+	if count == 0 {
+		return errors.New("no bids were accepted")
+	}
+	if price != last {
+		return fmt.Errorf("current price %.2f does not match the highest bid %.2f", price, last)
+	}
+	if bidderID == nil || *bidderID != lastUser {
+		return fmt.Errorf("current bidder does not match the author of the highest bid")
+	}
+
+	fmt.Printf("ok: %d bids strictly increasing, final price %.2f\n", count, price)
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fixtures struct {
+	db        *sql.DB
+	sellerID  uuid.UUID
+	itemID    uuid.UUID
+	auctionID uuid.UUID
+	userIDs   []uuid.UUID
+}
+
+func createUser(ctx context.Context, db *sql.DB, label string) (uuid.UUID, error) {
+	id := uuid.New()
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO users (id, name, email, password, role, created_at)
+		VALUES ($1, $2, $3, 'harness', 'USER', NOW())
+	`, id, "harness "+label, fmt.Sprintf("harness-%s@rebid.local", id))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("create user %s: %w", label, err)
+	}
+	return id, nil
+}
+
+// newAuctionFixtures creates a seller, an item, an ACTIVE auction ending in an
+// hour and the requested number of bidders.
+func newAuctionFixtures(ctx context.Context, db *sql.DB, bidders int, startingPrice float64) (*fixtures, error) {
+	f := &fixtures{db: db}
+
+	sellerID, err := createUser(ctx, db, "seller")
+	if err != nil {
+		return nil, err
+	}
+	f.sellerID = sellerID
+
+	f.itemID = uuid.New()
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO items (id, user_id, name, description, created_at, updated_at)
+		VALUES ($1, $2, 'harness item', 'created by cmd/harness', NOW(), NOW())
+	`, f.itemID, f.sellerID); err != nil {
+		return f, fmt.Errorf("create item: %w", err)
+	}
+
+	f.auctionID = uuid.New()
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO auctions (id, item_id, description, created_by, starting_price, current_price, start_time, end_time, original_end_time, status, created_at, updated_at)
+		VALUES ($1, $2, 'harness auction', $3, $4, $4, NOW(), $5, $5, 'ACTIVE', NOW(), NOW())
+	`, f.auctionID, f.itemID, f.sellerID, startingPrice, time.Now().UTC().Add(time.Hour)); err != nil {
+		return f, fmt.Errorf("create auction: %w", err)
+	}
+
+	for i := 0; i < bidders; i++ {
+		id, err := createUser(ctx, db, fmt.Sprintf("bidder %d", i+1))
+		if err != nil {
+			return f, err
+		}
+		f.userIDs = append(f.userIDs, id)
+	}
+
+	return f, nil
+}
+
+func (f *fixtures) cleanup(ctx context.Context) {
+	stmts := []struct {
+		q    string
+		args []interface{}
+	}{
+		{`DELETE FROM bids WHERE auction_id = $1`, []interface{}{f.auctionID}},
+		{`DELETE FROM proxy_bids WHERE auction_id = $1`, []interface{}{f.auctionID}},
+		{`DELETE FROM auctions WHERE id = $1`, []interface{}{f.auctionID}},
+		{`DELETE FROM items WHERE id = $1`, []interface{}{f.itemID}},
+	}
+	for _, st := range stmts {
+		if _, err := f.db.ExecContext(ctx, st.q, st.args...); err != nil {
+			fmt.Printf("cleanup: %v\n", err)
+		}
+	}
+	for _, id := range append([]uuid.UUID{f.sellerID}, f.userIDs...) {
+		if _, err := f.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, id); err != nil {
+			fmt.Printf("cleanup: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"rebid/internal/config"
+	database "rebid/internal/databases"
+	"rebid/internal/websocket"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// hubMessage is what the hubs harness broadcasts. Pad makes every tenth
+// message too large for a NOTIFY payload.
+type hubMessage struct {
+	From string `json:"from"`
+	N    int    `json:"n"`
+	Pad  string `json:"pad,omitempty"`
+}
+
+// runHubs starts two hubs on separate backplanes against one database, as
+// two replicas would, subscribes a client to the same auction on each and
+// has both hubs broadcast to it. Every client must see every message exactly
+// once, and each hub's messages in the order they were sent.
+func runHubs(args []string) error {
+	fs := flag.NewFlagSet("hubs", flag.ExitOnError)
+	messages := fs.Int("messages", 100, "messages broadcast by each hub")
+	backplane := fs.String("backplane", "postgres", "backplane to test: postgres or memory")
+	timeout := fs.Duration("timeout", 10*time.Second, "how long to wait for every message to arrive")
+	fs.Parse(args)
+
+	var backplaneA, backplaneB websocket.Backplane
+	switch *backplane {
+	case "postgres":
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		db, err := database.Open(cfg)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		a, err := websocket.NewPostgresBackplane(db, cfg.DBConnectionString())
+		if err != nil {
+			return err
+		}
+		defer a.Close()
+		b, err := websocket.NewPostgresBackplane(db, cfg.DBConnectionString())
+		if err != nil {
+			return err
+		}
+		defer b.Close()
+		backplaneA, backplaneB = a, b
+	case "memory":
+		shared := websocket.NewMemoryBackplane()
+		backplaneA, backplaneB = shared, shared
+	default:
+		return fmt.Errorf("unknown backplane %q", *backplane)
+	}
+
+	auctionID := uuid.New()
+	hubs := map[string]*websocket.Hub{
+		"A": websocket.NewHub(backplaneA),
+		"B": websocket.NewHub(backplaneB),
+	}
+	clients := make(map[string]*websocket.Client, len(hubs))
+	for name, hub := range hubs {
+		client := &websocket.Client{AuctionID: auctionID, Send: make(chan []byte, 2*(*messages))}
+		hub.Register(auctionID, client)
+		defer hub.Unregister(auctionID, client)
+		clients[name] = client
+	}
+
+	ctx := context.Background()
+	began := time.Now()
+	for i := 0; i < *messages; i++ {
+		for _, from := range []string{"A", "B"} {
+			msg := hubMessage{From: from, N: i}
+			if i%10 == 9 {
+				msg.Pad = strings.Repeat("x", 10000)
+			}
+			b, _ := json.Marshal(msg)
+			if err := hubs[from].BroadcastToAuction(ctx, auctionID, b); err != nil {
+				return fmt.Errorf("hub %s: broadcast #%d: %w", from, i, err)
+			}
+		}
+	}
+
+	deadline := time.After(*timeout)
+	for name, client := range clients {
+		next := map[string]int{"A": 0, "B": 0}
+		for received := 0; received < 2*(*messages); received++ {
+			select {
+			case b := <-client.Send:
+				var msg hubMessage
+				if err := json.Unmarshal(b, &msg); err != nil {
+					return fmt.Errorf("client on hub %s: unmarshal: %w", name, err)
+				}
+				if msg.N != next[msg.From] {
+					return fmt.Errorf("client on hub %s: got message #%d from hub %s, want #%d", name, msg.N, msg.From, next[msg.From])
+				}
+				next[msg.From]++
+			case <-deadline:
+				return fmt.Errorf("client on hub %s: got %d of %d messages before timing out", name, received, 2*(*messages))
+			}
+		}
+		select {
+		case <-client.Send:
+			return fmt.Errorf("client on hub %s: got a message twice", name)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	fmt.Printf("ok: %d messages from each of 2 hubs reached both clients in order over the %s backplane in %s\n",
+		*messages, *backplane, time.Since(began).Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// harness runs load and consistency checks against a local Postgres. It
+// creates its own throwaway fixtures and removes them unless -keep is set.
+//
+//	go run ./cmd/harness bids -bidders 20 -rounds 25
+//	go run ./cmd/harness hubs -messages 100
+//	go run ./cmd/harness notify -attempts 3
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "bids":
+		err = runBids(os.Args[2:])
+	case "hubs":
+		err = runHubs(os.Args[2:])
+	case "notify":
+		err = runNotify(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "harness:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: harness <command> [flags]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  bids    hammer one auction with parallel bids and verify the final price")
+	fmt.Fprintln(os.Stderr, "  hubs    broadcast from two hubs sharing a backplane and verify both see everything")
+	fmt.Fprintln(os.Stderr, "  notify  deliver, retry and give up on notifications through an in-memory sink")
+}
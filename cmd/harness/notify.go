@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"rebid/internal/config"
+	database "rebid/internal/databases"
+	"rebid/internal/notify"
+	"rebid/internal/repositories"
+	"rebid/internal/services"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// notifyChannel is the only channel the notify harness dispatches to.
+const notifyChannel = "harness"
+
+// errHarnessDelivery is what the memory sink fails deliveries with.
+var errHarnessDelivery = errors.New("harness: delivery refused")
+
+// runNotify drives the notification outbox through a memory sink: a message
+// that goes through first time, one that fails once and is retried, and one
+// that fails every attempt and is given up on. Between passes the retry is
+// pulled forward, after checking it was scheduled with the expected backoff.
+// The dispatcher claims every due notification, so the app's own dispatcher
+// must not be running and the outbox must have nothing else pending.
+func runNotify(args []string) error {
+	fs := flag.NewFlagSet("notify", flag.ExitOnError)
+	attempts := fs.Int("attempts", 3, "delivery attempts before a notification is given up on")
+	keep := fs.Bool("keep", false, "keep the generated user and notifications")
+	fs.Parse(args)
+
+	if *attempts < 2 {
+		return fmt.Errorf("attempts must be at least 2")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	cfg.NotifyMaxAttempts = *attempts
+	db, err := database.Open(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	var pending int
+	if err := db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM notification_outbox WHERE status = 'PENDING'`,
+	).Scan(&pending); err != nil {
+		return fmt.Errorf("read outbox: %w", err)
+	}
+	if pending > 0 {
+		return fmt.Errorf("the outbox has %d pending notification(s); dispatch them or use a quiet database", pending)
+	}
+
+	userID, err := createUser(ctx, db, "recipient")
+	if err != nil {
+		return err
+	}
+	if !*keep {
+		defer cleanupNotify(ctx, db, userID)
+	}
+
+	sink := notify.NewMemorySink()
+	svc := services.NewNotificationService(cfg, db, repositories.NewNotificationRepository(db),
+		map[string]notify.Notifier{notifyChannel: sink})
+
+	// Delivered on the first attempt.
+	id, err := enqueueNotify(ctx, db, svc, userID, "delivered")
+	if err != nil {
+		return err
+	}
+	if err := dispatchNotify(ctx, svc, 1); err != nil {
+		return err
+	}
+	if err := expectOutbox(ctx, db, id, "DELIVERED", 1); err != nil {
+		return err
+	}
+	if got := len(sink.Messages()); got != 1 {
+		return fmt.Errorf("sink received %d message(s), want 1", got)
+	}
+
+	// Refused once, then delivered on the retry.
+	sink.Fail(1, errHarnessDelivery)
+	id, err = enqueueNotify(ctx, db, svc, userID, "retried")
+	if err != nil {
+		return err
+	}
+	if err := dispatchNotify(ctx, svc, 0); err != nil {
+		return err
+	}
+	if err := expectRetry(ctx, db, id, 1); err != nil {
+		return err
+	}
+	if err := dispatchNotify(ctx, svc, 1); err != nil {
+		return err
+	}
+	if err := expectOutbox(ctx, db, id, "DELIVERED", 2); err != nil {
+		return err
+	}
+
+	// Refused on every attempt, then given up on.
+	sink.Fail(*attempts, errHarnessDelivery)
+	id, err = enqueueNotify(ctx, db, svc, userID, "given up")
+	if err != nil {
+		return err
+	}
+	for attempt := 1; attempt < *attempts; attempt++ {
+		if err := dispatchNotify(ctx, svc, 0); err != nil {
+			return err
+		}
+		if err := expectRetry(ctx, db, id, attempt); err != nil {
+			return err
+		}
+	}
+	if err := dispatchNotify(ctx, svc, 0); err != nil {
+		return err
+	}
+	if err := expectOutbox(ctx, db, id, "FAILED", *attempts); err != nil {
+		return err
+	}
+	if got := len(sink.Messages()); got != 2 {
+		return fmt.Errorf("sink received %d message(s), want 2", got)
+	}
+
+	fmt.Printf("ok: delivered, retried after a failure and gave up after %d attempts\n", *attempts)
+	return nil
+}
+
+// enqueueNotify queues one message for userID and returns its outbox row.
+func enqueueNotify(ctx context.Context, db *sql.DB, svc *services.NotificationService, userID uuid.UUID, subject string) (uuid.UUID, error) {
+	if err := svc.Notify(ctx, notify.Message{UserID: userID, Kind: "harness", Subject: subject, Body: "sent by cmd/harness"}); err != nil {
+		return uuid.Nil, err
+	}
+	var id uuid.UUID
+	if err := db.QueryRowContext(ctx,
+		`SELECT id FROM notification_outbox WHERE user_id = $1 AND subject = $2`, userID, subject,
+	).Scan(&id); err != nil {
+		return uuid.Nil, fmt.Errorf("read outbox: %w", err)
+	}
+	return id, nil
+}
+
+func dispatchNotify(ctx context.Context, svc *services.NotificationService, want int) error {
+	delivered, err := svc.Dispatch(ctx)
+	if err != nil {
+		return err
+	}
+	if delivered != want {
+		return fmt.Errorf("dispatch delivered %d notification(s), want %d", delivered, want)
+	}
+	return nil
+}
+
+// expectRetry checks that the notification is pending after attempt failed
+// attempts, due after the doubled backoff, then makes it due now.
+func expectRetry(ctx context.Context, db *sql.DB, id uuid.UUID, attempt int) error {
+	if err := expectOutbox(ctx, db, id, "PENDING", attempt); err != nil {
+		return err
+	}
+
+	var wait float64
+	if err := db.QueryRowContext(ctx,
+		`SELECT EXTRACT(EPOCH FROM next_attempt_at - NOW()) FROM notification_outbox WHERE id = $1`, id,
+	).Scan(&wait); err != nil {
+		return fmt.Errorf("read outbox: %w", err)
+	}
+	want := 30 * time.Second << (attempt - 1)
+	if got := time.Duration(wait * float64(time.Second)); got < want-5*time.Second || got > want+time.Second {
+		return fmt.Errorf("retry %d is due in %s, want about %s", attempt, got.Round(time.Second), want)
+	}
+
+	if _, err := db.ExecContext(ctx, `UPDATE notification_outbox SET next_attempt_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("reschedule retry: %w", err)
+	}
+	return nil
+}
+
+func expectOutbox(ctx context.Context, db *sql.DB, id uuid.UUID, status string, attempts int) error {
+	var gotStatus string
+	var gotAttempts int
+	var lastError sql.NullString
+	if err := db.QueryRowContext(ctx,
+		`SELECT status, attempts, last_error FROM notification_outbox WHERE id = $1`, id,
+	).Scan(&gotStatus, &gotAttempts, &lastError); err != nil {
+		return fmt.Errorf("read outbox: %w", err)
+	}
+	if gotStatus != status || gotAttempts != attempts {
+		return fmt.Errorf("notification is %s after %d attempt(s), want %s after %d", gotStatus, gotAttempts, status, attempts)
+	}
+	if status != "DELIVERED" && lastError.String != errHarnessDelivery.Error() {
+		return fmt.Errorf("notification last error is %q, want %q", lastError.String, errHarnessDelivery)
+	}
+	return nil
+}
+
+func cleanupNotify(ctx context.Context, db *sql.DB, userID uuid.UUID) {
+	for _, q := range []string{
+		`DELETE FROM notification_outbox WHERE user_id = $1`,
+		`DELETE FROM users WHERE id = $1`,
+	} {
+		if _, err := db.ExecContext(ctx, q, userID); err != nil {
+			fmt.Printf("cleanup: %v\n", err)
+		}
+	}
+}
@@ -4,7 +4,11 @@ import type { ApiSuccessResponse } from '@/lib/response'
 import type { Auction, AuctionDetail, AuctionWsMessage } from './auction.type'
 import { getWebSocketUrl } from '@/utils/wsUrl'
 import { CreateAuctionFormData } from './auction.schema'
-import { AUCTION_WS_CHANGE_ENDED } from './auction.constant'
+import {
+  AUCTION_WS_CHANGE_CONNECT,
+  AUCTION_WS_CHANGE_ENDED,
+  AUCTION_WS_CHANGE_RESYNC,
+} from './auction.constant'
 import { triggerAuctionEndedConfetti } from '@/store/celebration.slice'
 
 export const auctionApi = createApi({
@@ -35,15 +39,37 @@ export const auctionApi = createApi({
         const url = getWebSocketUrl(`/api/v1/auctions/${auctionId}/ws`)
         const ws = new WebSocket(url)
 
+        // seq is the last auction event applied. A message that skips one
+        // means we missed it, so ask for a fresh snapshot and wait for it;
+        // a full state already covers what was skipped.
+        let seq: number | null = null
+        let resyncing = false
+
         ws.onmessage = (ev: MessageEvent<string>) => {
           const msg = JSON.parse(ev.data) as AuctionWsMessage
+          if (msg.change === AUCTION_WS_CHANGE_CONNECT || msg.change === AUCTION_WS_CHANGE_RESYNC) {
+            resyncing = false
+          } else if (resyncing || seq === null || msg.seq <= seq) {
+            return
+          } else if (msg.seq > seq + 1 && !('auction' in msg)) {
+            resyncing = true
+            ws.send(JSON.stringify({ action: 'resync' }))
+            return
+          }
+          seq = msg.seq
+
           api.updateCachedData((draft) => {
             draft.auction.current_price = msg.current_price
             draft.auction.current_bidder_id = msg.current_bidder_id
-            draft.auction.status = msg.auction.status
-            if (msg.bids?.length) {
-              draft.bids = msg.bids
+            if ('auction' in msg) {
+              draft.auction.status = msg.auction.status
+              if (msg.bids?.length) {
+                draft.bids = msg.bids
+              }
+              return
             }
+            const placed = new Set(msg.bids.map((bid) => bid.id))
+            draft.bids = [...msg.bids, ...draft.bids.filter((bid) => !placed.has(bid.id))]
           })
           if (msg.change === AUCTION_WS_CHANGE_ENDED) {
             api.dispatch(triggerAuctionEndedConfetti())
@@ -1,4 +1,6 @@
 export const AUCTION_WS_CHANGE_ENDED = 'auction_ended'
+export const AUCTION_WS_CHANGE_CONNECT = 'connect'
+export const AUCTION_WS_CHANGE_RESYNC = 'resync'
 
 export function getStatusColor(status: string) {
 	switch (status) {
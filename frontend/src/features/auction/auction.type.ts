@@ -41,7 +41,9 @@ export interface Bid {
   user: UserDetail
 }
 
-export interface AuctionWsMessage {
+// Full state, sent on connect, on resync and for every change but a new bid.
+export interface AuctionWsSnapshot {
+  seq: number
   event: string
   change: string
   auction: Auction
@@ -50,6 +52,19 @@ export interface AuctionWsMessage {
   bids: Bid[]
 }
 
+// Just the bids a new_bid change placed, with the price after them.
+export interface AuctionWsBidDelta {
+  seq: number
+  event: string
+  change: string
+  auction_id: string
+  current_price: number
+  current_bidder_id: string
+  bids: Bid[]
+}
+
+export type AuctionWsMessage = AuctionWsSnapshot | AuctionWsBidDelta
+
 export interface AuctionDetail {
   auction: Auction
   bids: Bid[]
@@ -2,7 +2,12 @@ package bootstrap
 
 import (
 	"database/sql"
+	"fmt"
+	"log"
 	"rebid/internal/config"
+	"rebid/internal/events"
+	"rebid/internal/notify"
+	"rebid/internal/payments"
 	"rebid/internal/repositories"
 	"rebid/internal/services"
 	"rebid/internal/websocket"
@@ -15,25 +20,72 @@ type Dependencies struct {
 	ItemImageRepo  *repositories.ItemImageRepository
 	AuctionRepo    *repositories.AuctionRepository
 	BidRepo        *repositories.BidRepository
+	ProxyBidRepo   *repositories.ProxyBidRepository
+	ResultRepo     *repositories.AuctionResultRepository
+	EventRepo      *repositories.AuctionEventRepository
+	LedgerRepo     *repositories.LedgerRepository
+	PaymentRepo    *repositories.PaymentRepository
+	OfferRepo      *repositories.SecondChanceOfferRepository
+	NotifyRepo     *repositories.NotificationRepository
+	WatchlistRepo  *repositories.WatchlistRepository
+	DomainEvents   *repositories.DomainEventRepository
+	LeaseRepo      *repositories.WorkerLeaseRepository
 	UserService    *services.UserService
 	ItemService    *services.ItemService
 	AuctionService *services.AuctionService
 	BidService     *services.BidService
+	EventService   *services.AuctionEventService
+	WalletService  *services.WalletService
+	PaymentService *services.PaymentService
+	OfferService   *services.SecondChanceService
+	NotifyService  *services.NotificationService
+	WatchService   *services.WatchlistService
+	EventRelay     *services.DomainEventService
+	WorkerService  *services.WorkerService
+	HubService     *services.HubService
 }
 
 func BuildDependencies(cfg *config.Config, db *sql.DB) *Dependencies {
-	hub := websocket.NewHub()
+	backplane, err := buildBackplane(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to set up the hub backplane: %v", err)
+	}
+	hub := websocket.NewHub(backplane)
 
 	itemImageRepo := repositories.NewItemImageRepository(db)
 	userRepo := repositories.NewUserRepository(db)
 	itemRepo := repositories.NewItemRepository(db, itemImageRepo)
 	auctionRepo := repositories.NewAuctionRepository(db, itemImageRepo)
 	bidRepo := repositories.NewBidRepository(db)
+	proxyBidRepo := repositories.NewProxyBidRepository(db)
+	resultRepo := repositories.NewAuctionResultRepository(db)
+	eventRepo := repositories.NewAuctionEventRepository(db)
+	ledgerRepo := repositories.NewLedgerRepository(db)
+	paymentRepo := repositories.NewPaymentRepository(db)
+	offerRepo := repositories.NewSecondChanceOfferRepository(db)
+	watchlistRepo := repositories.NewWatchlistRepository(db)
+	notifyRepo := repositories.NewNotificationRepository(db)
+	domainEventRepo := repositories.NewDomainEventRepository(db)
+	leaseRepo := repositories.NewWorkerLeaseRepository(db)
+
+	gateway, err := payments.NewGateway(cfg.PaymentProvider, cfg.PaymentWebhookSecret)
+	if err != nil {
+		log.Fatalf("Failed to set up payments: %v", err)
+	}
 
 	userService := services.NewUserService(cfg, userRepo)
 	itemService := services.NewItemService(cfg, itemRepo, itemImageRepo)
-	auctionService := services.NewAuctionService(cfg, auctionRepo)
-	bidService := services.NewBidService(cfg, db, bidRepo, auctionRepo)
+	notifyService := services.NewNotificationService(cfg, db, notifyRepo, buildNotifiers(cfg, hub, notifyRepo))
+	eventRelay := services.NewDomainEventService(cfg, domainEventRepo, buildEventSinks(cfg, hub, auctionRepo, bidRepo))
+	walletService := services.NewWalletService(cfg, db, ledgerRepo)
+	auctionService := services.NewAuctionService(cfg, db, auctionRepo, bidRepo, proxyBidRepo, resultRepo, walletService, notifyService, eventRelay)
+	bidService := services.NewBidService(cfg, db, bidRepo, proxyBidRepo, auctionRepo, auctionService, walletService, notifyService, eventRelay)
+	eventService := services.NewAuctionEventService(cfg, eventRepo, auctionRepo)
+	paymentService := services.NewPaymentService(cfg, db, paymentRepo, auctionRepo, offerRepo, walletService, gateway, notifyService)
+	offerService := services.NewSecondChanceService(cfg, db, offerRepo, auctionRepo, bidRepo, resultRepo, walletService, notifyService)
+	watchService := services.NewWatchlistService(cfg, watchlistRepo, auctionRepo, notifyService)
+	workerService := services.NewWorkerService(cfg, leaseRepo)
+	hubService := services.NewHubService(cfg, hub)
 
 	return &Dependencies{
 		Hub:            hub,
@@ -42,9 +94,95 @@ func BuildDependencies(cfg *config.Config, db *sql.DB) *Dependencies {
 		ItemImageRepo:  itemImageRepo,
 		AuctionRepo:    auctionRepo,
 		BidRepo:        bidRepo,
+		ProxyBidRepo:   proxyBidRepo,
+		ResultRepo:     resultRepo,
+		EventRepo:      eventRepo,
+		LedgerRepo:     ledgerRepo,
+		PaymentRepo:    paymentRepo,
+		OfferRepo:      offerRepo,
+		WatchlistRepo:  watchlistRepo,
+		NotifyRepo:     notifyRepo,
+		DomainEvents:   domainEventRepo,
+		LeaseRepo:      leaseRepo,
 		UserService:    userService,
 		ItemService:    itemService,
 		AuctionService: auctionService,
 		BidService:     bidService,
+		EventService:   eventService,
+		WalletService:  walletService,
+		PaymentService: paymentService,
+		OfferService:   offerService,
+		NotifyService:  notifyService,
+		WatchService:   watchService,
+		EventRelay:     eventRelay,
+		WorkerService:  workerService,
+		HubService:     hubService,
+	}
+}
+
+// buildNotifiers returns the notifier for each configured channel. Email and
+// webhook delivery are skipped, with a warning, until they are configured.
+// In-app notifications are also pushed to the user's open channels on hub.
+func buildNotifiers(cfg *config.Config, hub *websocket.Hub, repo *repositories.NotificationRepository) map[string]notify.Notifier {
+	channels := make(map[string]notify.Notifier)
+	for _, channel := range cfg.NotifyChannels {
+		switch channel {
+		case notify.ChannelInApp:
+			channels[channel] = notify.NewInboxNotifier(repo, websocket.NewUserNotifier(hub, repo))
+		case notify.ChannelEmail:
+			if cfg.SMTPHost == "" {
+				log.Printf("notifications: SMTP_HOST is not set, email disabled")
+				continue
+			}
+			channels[channel] = notify.NewSMTPNotifier(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom, repo.GetUserEmail)
+		case notify.ChannelWebhook:
+			if cfg.NotifyWebhookURL == "" {
+				log.Printf("notifications: NOTIFY_WEBHOOK_URL is not set, webhook disabled")
+				continue
+			}
+			channels[channel] = notify.NewWebhookNotifier(cfg.NotifyWebhookURL)
+		case notify.ChannelLog:
+			channels[channel] = notify.NewLogNotifier()
+		default:
+			log.Fatalf("Unknown notification channel %q", channel)
+		}
+	}
+	return channels
+}
+
+// buildEventSinks returns the sink for each configured domain event sink.
+// The webhook sink is skipped, with a warning, until it is configured.
+func buildEventSinks(cfg *config.Config, hub *websocket.Hub, auctionRepo *repositories.AuctionRepository, bidRepo *repositories.BidRepository) map[string]events.Sink {
+	sinks := make(map[string]events.Sink)
+	for _, sink := range cfg.EventSinks {
+		switch sink {
+		case events.SinkHub:
+			sinks[sink] = websocket.NewHubSink(hub, cfg, auctionRepo, bidRepo)
+		case events.SinkWebhook:
+			if cfg.EventWebhookURL == "" {
+				log.Printf("domain events: EVENT_WEBHOOK_URL is not set, webhook sink disabled")
+				continue
+			}
+			sinks[sink] = events.NewWebhookSink(cfg.EventWebhookURL)
+		case events.SinkLog:
+			sinks[sink] = events.NewLogSink()
+		default:
+			log.Fatalf("Unknown domain event sink %q", sink)
+		}
+	}
+	return sinks
+}
+
+// buildBackplane returns the backplane hubs publish through: in memory for a
+// single node, or Postgres LISTEN/NOTIFY when several replicas serve the
+// same subscribers.
+func buildBackplane(cfg *config.Config, db *sql.DB) (websocket.Backplane, error) {
+	switch cfg.HubBackplane {
+	case "memory":
+		return websocket.NewMemoryBackplane(), nil
+	case "postgres":
+		return websocket.NewPostgresBackplane(db, cfg.DBConnectionString())
+	default:
+		return nil, fmt.Errorf("unknown hub backplane %q", cfg.HubBackplane)
 	}
 }
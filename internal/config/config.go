@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,8 +33,54 @@ type Config struct {
 	GoogleClientID     string
 	GoogleClientSecret string
 	GoogleRedirectURI  string
-	// worker
-	AuctionCloserCron string
+	// worker; each scheduled job runs on one node at a time, and NodeID
+	// names this one in the worker status
+	NodeID               string
+	AuctionCloserCron    string
+	AuctionActivatorCron string
+	DutchTickerCron      string
+	SecondChanceCron     string
+	WatchlistCron        string
+	// auction rules
+	BuyNowDisableFraction float64
+	// bidders may retract a bid within the window after placing it, but not
+	// once the auction is inside the cutoff before its end
+	BidRetractionWindow time.Duration
+	BidRetractionCutoff time.Duration
+	// settlement fees, as percentages of the hammer price
+	BuyerPremiumPercent float64
+	SellerFeePercent    float64
+	// payments
+	PaymentProvider      string
+	PaymentWebhookSecret string
+	PaymentCurrency      string
+	// a winner who has not paid within the deadline loses the item to a
+	// second-chance offer, which the runner-up has the TTL to accept
+	PaymentDeadline      time.Duration
+	SecondChanceOfferTTL time.Duration
+	// watchers are reminded as an auction crosses each of these durations
+	// before its end_time
+	ReminderThresholds []time.Duration
+	// notifications are delivered through each of the channels, retried
+	// up to the max attempts
+	NotifyChannels    []string
+	NotifyMaxAttempts int
+	NotifyInterval    time.Duration
+	SMTPHost          string
+	SMTPPort          string
+	SMTPUsername      string
+	SMTPPassword      string
+	SMTPFrom          string
+	NotifyWebhookURL  string
+	// domain events are relayed to each of the sinks every interval,
+	// retried up to the max attempts
+	EventSinks         []string
+	EventMaxAttempts   int
+	EventRelayInterval time.Duration
+	EventWebhookURL    string
+	// hubs reach each other's subscribers through the backplane: memory
+	// for a single node, postgres for several replicas
+	HubBackplane string
 }
 
 func (c *Config) DBConnectionString() string {
@@ -53,32 +100,72 @@ func Load() (*Config, error) {
 	}
 
 	config := &Config{
-		Port:                 getEnv("PORT", "8080"),
-		Host:                 getEnv("HOST", "localhost"),
-		DBHost:               getEnv("DB_HOST", "localhost"),
-		DBPort:               getEnv("DB_PORT", "5432"),
-		DBUser:               getEnv("DB_USER", "rebid_user"),
-		DBPass:               getEnv("DB_PASSWORD", "rebid_password"),
-		DBName:               getEnv("DB_NAME", "rebid_db"),
-		DBSSLMode:            getEnv("DB_SSLMODE", "disable"),
-		JWTSecret:            getEnv("JWT_SECRET", "your-secret-key-here"),
-		JWTExpiry:            jwtExpiry,
-		UploadDir:            getEnv("UPLOAD_DIR", "./uploads"),
-		BaseURL:              getEnv("BASE_URL", "http://localhost:8080"),
-		CookieName:           getEnv("COOKIE_NAME", "token"),
-		CookieSecure:         getEnv("COOKIE_SECURE", "false") == "true",
-		CookieSameSite:       getEnv("COOKIE_SAME_SITE", "lax"),
-		FrontendOrigins:      parseFrontendOrigins(getEnv("FRONTEND_ORIGINS", "http://localhost:3000")),
-		CORSAllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", "true") == "true",
-		GoogleClientID:       getEnv("GOOGLE_CLIENT_ID", ""),
-		GoogleClientSecret:   getEnv("GOOGLE_CLIENT_SECRET", ""),
-		GoogleRedirectURI:    getEnv("GOOGLE_REDIRECT_URI", "http://localhost:8080/api/v1/auth/google/callback"),
-		AuctionCloserCron:    getEnv("AUCTION_CLOSER_CRON", "0 * * * * *"),
+		Port:                  getEnv("PORT", "8080"),
+		Host:                  getEnv("HOST", "localhost"),
+		DBHost:                getEnv("DB_HOST", "localhost"),
+		DBPort:                getEnv("DB_PORT", "5432"),
+		DBUser:                getEnv("DB_USER", "rebid_user"),
+		DBPass:                getEnv("DB_PASSWORD", "rebid_password"),
+		DBName:                getEnv("DB_NAME", "rebid_db"),
+		DBSSLMode:             getEnv("DB_SSLMODE", "disable"),
+		JWTSecret:             getEnv("JWT_SECRET", "your-secret-key-here"),
+		JWTExpiry:             jwtExpiry,
+		UploadDir:             getEnv("UPLOAD_DIR", "./uploads"),
+		BaseURL:               getEnv("BASE_URL", "http://localhost:8080"),
+		CookieName:            getEnv("COOKIE_NAME", "token"),
+		CookieSecure:          getEnv("COOKIE_SECURE", "false") == "true",
+		CookieSameSite:        getEnv("COOKIE_SAME_SITE", "lax"),
+		FrontendOrigins:       parseFrontendOrigins(getEnv("FRONTEND_ORIGINS", "http://localhost:3000")),
+		CORSAllowCredentials:  getEnv("CORS_ALLOW_CREDENTIALS", "true") == "true",
+		GoogleClientID:        getEnv("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret:    getEnv("GOOGLE_CLIENT_SECRET", ""),
+		GoogleRedirectURI:     getEnv("GOOGLE_REDIRECT_URI", "http://localhost:8080/api/v1/auth/google/callback"),
+		NodeID:                getEnv("NODE_ID", defaultNodeID()),
+		AuctionCloserCron:     getEnv("AUCTION_CLOSER_CRON", "0 * * * * *"),
+		AuctionActivatorCron:  getEnv("AUCTION_ACTIVATOR_CRON", "0 * * * * *"),
+		DutchTickerCron:       getEnv("DUTCH_TICKER_CRON", "*/5 * * * * *"),
+		SecondChanceCron:      getEnv("SECOND_CHANCE_CRON", "0 */5 * * * *"),
+		WatchlistCron:         getEnv("WATCHLIST_REMINDER_CRON", "0 * * * * *"),
+		BuyNowDisableFraction: parseFraction(getEnv("BUY_NOW_DISABLE_FRACTION", "0.5"), 0.5),
+		BidRetractionWindow:   parseMinutes(getEnv("BID_RETRACTION_WINDOW_MINUTES", "60"), 60),
+		BidRetractionCutoff:   parseMinutes(getEnv("BID_RETRACTION_CUTOFF_MINUTES", "5"), 5),
+		BuyerPremiumPercent:   parsePercent(getEnv("BUYER_PREMIUM_PERCENT", "0"), 0),
+		SellerFeePercent:      parsePercent(getEnv("SELLER_FEE_PERCENT", "0"), 0),
+		PaymentProvider:       getEnv("PAYMENT_PROVIDER", "fake"),
+		PaymentWebhookSecret:  getEnv("PAYMENT_WEBHOOK_SECRET", "your-webhook-secret-here"),
+		PaymentCurrency:       getEnv("PAYMENT_CURRENCY", "USD"),
+		PaymentDeadline:       parseHours(getEnv("PAYMENT_DEADLINE_HOURS", "72"), 72),
+		SecondChanceOfferTTL:  parseHours(getEnv("SECOND_CHANCE_OFFER_TTL_HOURS", "48"), 48),
+		ReminderThresholds:    parseThresholds(getEnv("WATCHLIST_REMINDER_THRESHOLDS", "24h,1h,5m")),
+		NotifyChannels:        parseChannels(getEnv("NOTIFY_CHANNELS", "in_app")),
+		NotifyMaxAttempts:     parseCount(getEnv("NOTIFY_MAX_ATTEMPTS", "5"), 5),
+		NotifyInterval:        time.Duration(parseCount(getEnv("NOTIFY_DISPATCH_INTERVAL_SECONDS", "5"), 5)) * time.Second,
+		SMTPHost:              getEnv("SMTP_HOST", ""),
+		SMTPPort:              getEnv("SMTP_PORT", "1025"),
+		SMTPUsername:          getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:          getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:              getEnv("SMTP_FROM", "no-reply@rebid.local"),
+		NotifyWebhookURL:      getEnv("NOTIFY_WEBHOOK_URL", ""),
+		EventSinks:            parseChannels(getEnv("EVENT_SINKS", "hub")),
+		EventMaxAttempts:      parseCount(getEnv("EVENT_MAX_ATTEMPTS", "10"), 10),
+		EventRelayInterval:    time.Duration(parseCount(getEnv("EVENT_RELAY_INTERVAL_MS", "250"), 250)) * time.Millisecond,
+		EventWebhookURL:       getEnv("EVENT_WEBHOOK_URL", ""),
+		HubBackplane:          strings.ToLower(getEnv("HUB_BACKPLANE", "memory")),
 	}
 
 	return config, nil
 }
 
+// defaultNodeID names the node after its host and process, which is unique
+// enough to tell replicas apart.
+func defaultNodeID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -130,3 +217,77 @@ func parseDuration(s string) time.Duration {
 	}
 	return 0
 }
+
+// parseFraction reads a value in [0, 1], falling back to def when s is not one.
+func parseFraction(s string, def float64) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f < 0 || f > 1 {
+		return def
+	}
+	return f
+}
+
+// parsePercent reads a value in [0, 100], falling back to def when s is not one.
+func parsePercent(s string, def float64) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || f < 0 || f > 100 {
+		return def
+	}
+	return f
+}
+
+// parseMinutes reads a non-negative number of minutes, falling back to def
+// when s is not one.
+func parseMinutes(s string, def int) time.Duration {
+	minutes, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || minutes < 0 {
+		minutes = def
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// parseThresholds reads a comma-separated list of positive Go durations,
+// skipping any that do not parse.
+func parseThresholds(raw string) []time.Duration {
+	var out []time.Duration
+	for _, part := range strings.Split(raw, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || d < time.Second {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// parseChannels reads a comma-separated list of notification channels,
+// upper-cased to match their names, such as "in_app,email".
+func parseChannels(raw string) []string {
+	var out []string
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.ToUpper(strings.TrimSpace(part)); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// parseCount reads a positive integer, falling back to def when s is not
+// one.
+func parseCount(s string, def int) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n <= 0 {
+		return def
+	}
+	return n
+}
+
+// parseHours reads a positive number of hours, falling back to def when s is
+// not one.
+func parseHours(s string, def int) time.Duration {
+	hours, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || hours <= 0 {
+		hours = def
+	}
+	return time.Duration(hours) * time.Hour
+}
@@ -0,0 +1,3 @@
+ALTER TABLE bids DROP COLUMN IF EXISTS is_auto;
+
+DROP TABLE IF EXISTS proxy_bids;
@@ -0,0 +1,13 @@
+CREATE TABLE proxy_bids (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    max_amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_proxy_bid_auction_user UNIQUE (auction_id, user_id)
+);
+
+CREATE INDEX idx_proxy_bids_auction_id ON proxy_bids(auction_id);
+
+ALTER TABLE bids ADD COLUMN is_auto BOOLEAN NOT NULL DEFAULT FALSE;
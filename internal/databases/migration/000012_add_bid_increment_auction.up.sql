@@ -0,0 +1 @@
+ALTER TABLE auctions ADD COLUMN bid_increment JSONB NOT NULL DEFAULT '{"type": "FIXED", "amount": 1}';
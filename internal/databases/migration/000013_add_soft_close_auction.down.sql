@@ -0,0 +1,2 @@
+ALTER TABLE auctions DROP COLUMN IF EXISTS original_end_time;
+ALTER TABLE auctions DROP COLUMN IF EXISTS soft_close;
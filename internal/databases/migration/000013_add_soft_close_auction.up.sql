@@ -0,0 +1,6 @@
+ALTER TABLE auctions ADD COLUMN soft_close JSONB NULL;
+ALTER TABLE auctions ADD COLUMN original_end_time TIMESTAMP NULL;
+
+UPDATE auctions SET original_end_time = end_time;
+
+ALTER TABLE auctions ALTER COLUMN original_end_time SET NOT NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS auction_status_transitions;
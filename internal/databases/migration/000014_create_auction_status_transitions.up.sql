@@ -0,0 +1,11 @@
+CREATE TABLE auction_status_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    actor_type VARCHAR(20) NOT NULL CHECK (actor_type IN ('OWNER', 'ADMIN', 'SYSTEM')),
+    actor_id UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_auction_status_transitions_auction_id ON auction_status_transitions(auction_id);
@@ -0,0 +1,3 @@
+ALTER TABLE auctions DROP COLUMN IF EXISTS winner_id;
+ALTER TABLE auctions DROP COLUMN IF EXISTS outcome;
+ALTER TABLE auctions DROP COLUMN IF EXISTS reserve_price;
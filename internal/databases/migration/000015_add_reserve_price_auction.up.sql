@@ -0,0 +1,8 @@
+ALTER TABLE auctions ADD COLUMN reserve_price DECIMAL(10, 2) NULL;
+ALTER TABLE auctions ADD COLUMN outcome VARCHAR(20) NULL CHECK (outcome IN ('SOLD', 'RESERVE_NOT_MET', 'NO_BIDS'));
+ALTER TABLE auctions ADD COLUMN winner_id UUID NULL REFERENCES users(id);
+
+UPDATE auctions
+SET outcome = CASE WHEN current_bidder_id IS NULL THEN 'NO_BIDS' ELSE 'SOLD' END,
+    winner_id = current_bidder_id
+WHERE status = 'ENDED';
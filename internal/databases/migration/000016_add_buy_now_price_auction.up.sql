@@ -0,0 +1 @@
+ALTER TABLE auctions ADD COLUMN buy_now_price DECIMAL(10, 2) NULL;
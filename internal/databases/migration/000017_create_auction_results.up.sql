@@ -0,0 +1,22 @@
+CREATE TABLE auction_results (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    auction_id UUID NOT NULL UNIQUE REFERENCES auctions(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users(id),
+    winner_id UUID REFERENCES users(id),
+    outcome VARCHAR(20) NOT NULL CHECK (outcome IN ('SOLD', 'RESERVE_NOT_MET', 'NO_BIDS')),
+    hammer_price DECIMAL(10, 2),
+    buyer_fee DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    seller_fee DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    closed_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_auction_results_winner_id ON auction_results(winner_id);
+CREATE INDEX idx_auction_results_seller_id ON auction_results(seller_id);
+
+INSERT INTO auction_results (auction_id, seller_id, winner_id, outcome, hammer_price, closed_at)
+SELECT id, created_by, winner_id, outcome,
+       CASE WHEN outcome = 'SOLD' THEN current_price END,
+       LEAST(end_time, updated_at)
+FROM auctions
+WHERE status = 'ENDED' AND outcome IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE auctions DROP COLUMN IF EXISTS price_dropped_at;
+ALTER TABLE auctions DROP COLUMN IF EXISTS dutch_schedule;
+ALTER TABLE auctions DROP COLUMN IF EXISTS auction_type;
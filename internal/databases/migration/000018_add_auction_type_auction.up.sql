@@ -0,0 +1,3 @@
+ALTER TABLE auctions ADD COLUMN auction_type VARCHAR(20) NOT NULL DEFAULT 'ENGLISH' CHECK (auction_type IN ('ENGLISH', 'DUTCH'));
+ALTER TABLE auctions ADD COLUMN dutch_schedule JSONB NULL;
+ALTER TABLE auctions ADD COLUMN price_dropped_at TIMESTAMP NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE auctions DROP CONSTRAINT IF EXISTS auctions_auction_type_check;
+ALTER TABLE auctions ADD CONSTRAINT auctions_auction_type_check
+    CHECK (auction_type IN ('ENGLISH', 'DUTCH', 'SEALED_FIRST_PRICE', 'SEALED_SECOND_PRICE'));
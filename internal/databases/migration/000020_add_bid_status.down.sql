@@ -0,0 +1,4 @@
+ALTER TABLE bids DROP COLUMN IF EXISTS void_reason;
+ALTER TABLE bids DROP COLUMN IF EXISTS voided_by;
+ALTER TABLE bids DROP COLUMN IF EXISTS voided_at;
+ALTER TABLE bids DROP COLUMN IF EXISTS status;
@@ -0,0 +1,4 @@
+ALTER TABLE bids ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'VALID' CHECK (status IN ('VALID', 'RETRACTED', 'CANCELLED'));
+ALTER TABLE bids ADD COLUMN voided_at TIMESTAMP NULL;
+ALTER TABLE bids ADD COLUMN voided_by UUID NULL REFERENCES users(id);
+ALTER TABLE bids ADD COLUMN void_reason TEXT NULL;
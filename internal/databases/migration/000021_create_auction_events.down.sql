@@ -0,0 +1,5 @@
+ALTER TABLE auctions DROP CONSTRAINT IF EXISTS unique_event_lot;
+ALTER TABLE auctions DROP COLUMN IF EXISTS lot_number;
+ALTER TABLE auctions DROP COLUMN IF EXISTS event_id;
+
+DROP TABLE IF EXISTS auction_events;
@@ -0,0 +1,18 @@
+CREATE TABLE auction_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_by UUID NOT NULL REFERENCES users(id),
+    start_time TIMESTAMP NOT NULL,
+    first_lot_end_time TIMESTAMP NOT NULL,
+    close_stagger_seconds INT NOT NULL DEFAULT 60 CHECK (close_stagger_seconds >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (first_lot_end_time > start_time)
+);
+
+CREATE INDEX idx_auction_events_created_by ON auction_events(created_by);
+
+ALTER TABLE auctions ADD COLUMN event_id UUID NULL REFERENCES auction_events(id) ON DELETE SET NULL;
+ALTER TABLE auctions ADD COLUMN lot_number INT NULL;
+ALTER TABLE auctions ADD CONSTRAINT unique_event_lot UNIQUE (event_id, lot_number);
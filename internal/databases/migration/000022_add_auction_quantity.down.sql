@@ -0,0 +1,12 @@
+DELETE FROM auction_results r
+USING auction_results o
+WHERE r.auction_id = o.auction_id AND r.id > o.id;
+
+ALTER TABLE auction_results DROP CONSTRAINT IF EXISTS unique_auction_result_winner;
+ALTER TABLE auction_results ADD CONSTRAINT auction_results_auction_id_key UNIQUE (auction_id);
+ALTER TABLE auction_results DROP COLUMN IF EXISTS quantity;
+
+ALTER TABLE bids DROP COLUMN IF EXISTS quantity;
+
+ALTER TABLE auctions DROP COLUMN IF EXISTS units_demanded;
+ALTER TABLE auctions DROP COLUMN IF EXISTS quantity;
@@ -0,0 +1,10 @@
+ALTER TABLE auctions ADD COLUMN quantity INT NOT NULL DEFAULT 1 CHECK (quantity >= 1);
+ALTER TABLE auctions ADD COLUMN units_demanded INT NOT NULL DEFAULT 0;
+
+ALTER TABLE bids ADD COLUMN quantity INT NOT NULL DEFAULT 1 CHECK (quantity >= 1);
+
+ALTER TABLE auction_results ADD COLUMN quantity INT NOT NULL DEFAULT 1;
+UPDATE auction_results SET quantity = 0 WHERE outcome <> 'SOLD';
+
+ALTER TABLE auction_results DROP CONSTRAINT IF EXISTS auction_results_auction_id_key;
+ALTER TABLE auction_results ADD CONSTRAINT unique_auction_result_winner UNIQUE (auction_id, winner_id);
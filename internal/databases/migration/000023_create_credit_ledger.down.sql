@@ -0,0 +1,6 @@
+ALTER TABLE auctions DROP COLUMN IF EXISTS deposit_required;
+
+DROP TABLE IF EXISTS credit_holds;
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP TABLE IF EXISTS ledger_accounts;
@@ -0,0 +1,52 @@
+CREATE TABLE ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('AVAILABLE', 'HELD', 'FUNDING')),
+    balance DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT unique_ledger_account_user_kind UNIQUE (user_id, kind),
+    CONSTRAINT non_negative_balance CHECK (kind = 'FUNDING' OR balance >= 0),
+    CHECK ((kind = 'FUNDING') = (user_id IS NULL))
+);
+
+-- The funding account is the system side of every credit granted to users.
+CREATE UNIQUE INDEX idx_ledger_accounts_funding ON ledger_accounts(kind) WHERE user_id IS NULL;
+INSERT INTO ledger_accounts (kind) VALUES ('FUNDING');
+
+CREATE TABLE ledger_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('CREDIT', 'HOLD', 'RELEASE')),
+    user_id UUID NOT NULL REFERENCES users(id),
+    auction_id UUID REFERENCES auctions(id) ON DELETE SET NULL,
+    created_by UUID REFERENCES users(id),
+    memo TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ledger_transactions_user_id ON ledger_transactions(user_id);
+
+-- Every transaction posts one debit and one credit entry summing to zero.
+CREATE TABLE ledger_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    transaction_id UUID NOT NULL REFERENCES ledger_transactions(id),
+    account_id UUID NOT NULL REFERENCES ledger_accounts(id),
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount <> 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ledger_entries_transaction_id ON ledger_entries(transaction_id);
+CREATE INDEX idx_ledger_entries_account_id ON ledger_entries(account_id);
+
+CREATE TABLE credit_holds (
+    auction_id UUID NOT NULL REFERENCES auctions(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (auction_id, user_id)
+);
+
+CREATE INDEX idx_credit_holds_user_id ON credit_holds(user_id);
+
+ALTER TABLE auctions ADD COLUMN deposit_required BOOLEAN NOT NULL DEFAULT FALSE;
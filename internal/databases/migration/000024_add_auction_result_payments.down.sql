@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS payment_webhook_events;
+DROP TABLE IF EXISTS payment_status_transitions;
+
+DROP INDEX IF EXISTS idx_auction_results_payment_provider_ref;
+ALTER TABLE auction_results DROP COLUMN IF EXISTS payment_provider_ref;
+ALTER TABLE auction_results DROP COLUMN IF EXISTS payment_status;
@@ -0,0 +1,27 @@
+ALTER TABLE auction_results ADD COLUMN payment_status VARCHAR(20) NULL
+    CHECK (payment_status IN ('PENDING', 'AUTHORIZED', 'CAPTURED', 'FAILED', 'REFUNDED'));
+ALTER TABLE auction_results ADD COLUMN payment_provider_ref VARCHAR(255) NULL;
+
+UPDATE auction_results SET payment_status = 'PENDING' WHERE outcome = 'SOLD';
+
+CREATE UNIQUE INDEX idx_auction_results_payment_provider_ref ON auction_results(payment_provider_ref);
+
+CREATE TABLE payment_status_transitions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    result_id UUID NOT NULL REFERENCES auction_results(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('CHECKOUT', 'WEBHOOK', 'ADMIN')),
+    provider_ref VARCHAR(255),
+    amount DECIMAL(12, 2),
+    detail TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_status_transitions_result_id ON payment_status_transitions(result_id);
+
+-- Provider webhook event IDs already applied, so redeliveries are ignored.
+CREATE TABLE payment_webhook_events (
+    id VARCHAR(255) PRIMARY KEY,
+    received_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
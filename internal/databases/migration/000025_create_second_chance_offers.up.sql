@@ -0,0 +1,23 @@
+CREATE TABLE second_chance_offers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    result_id UUID NOT NULL REFERENCES auction_results(id) ON DELETE CASCADE,
+    bid_id UUID NOT NULL REFERENCES bids(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    previous_winner_id UUID REFERENCES users(id),
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'ACCEPTED', 'DECLINED', 'EXPIRED')),
+    source VARCHAR(20) NOT NULL CHECK (source IN ('SELLER', 'TIMEOUT')),
+    created_by UUID REFERENCES users(id),
+    expires_at TIMESTAMP NOT NULL,
+    responded_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- a bidder is offered an item at most once
+    CONSTRAINT unique_second_chance_offer_user UNIQUE (auction_id, user_id)
+);
+
+-- Only one offer per auction may be open at a time.
+CREATE UNIQUE INDEX unique_pending_second_chance_offer ON second_chance_offers(auction_id) WHERE status = 'PENDING';
+
+CREATE INDEX idx_second_chance_offers_user_id ON second_chance_offers(user_id);
+CREATE INDEX idx_second_chance_offers_expires_at ON second_chance_offers(expires_at) WHERE status = 'PENDING';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS watchlist_reminders;
+DROP TABLE IF EXISTS watchlist;
@@ -0,0 +1,18 @@
+CREATE TABLE watchlist (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, auction_id)
+);
+
+CREATE INDEX idx_watchlist_auction_id ON watchlist(auction_id);
+
+-- Ending-soon reminders already sent, one per watcher and threshold.
+CREATE TABLE watchlist_reminders (
+    user_id UUID NOT NULL,
+    auction_id UUID NOT NULL,
+    threshold_seconds INT NOT NULL CHECK (threshold_seconds > 0),
+    sent_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, auction_id, threshold_seconds),
+    FOREIGN KEY (user_id, auction_id) REFERENCES watchlist(user_id, auction_id) ON DELETE CASCADE
+);
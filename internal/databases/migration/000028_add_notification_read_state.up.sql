@@ -0,0 +1,3 @@
+ALTER TABLE notifications ADD COLUMN read_at TIMESTAMP NULL;
+
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
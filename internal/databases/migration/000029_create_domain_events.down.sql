@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS domain_event_deliveries;
+DROP TABLE IF EXISTS domain_events;
@@ -0,0 +1,25 @@
+-- Changes to an auction that subscribers and external systems are told
+-- about, written in the same transaction as the change. The id is the
+-- dedup key sinks see on every delivery; seq orders events that share a
+-- timestamp.
+CREATE TABLE domain_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    seq BIGSERIAL NOT NULL UNIQUE,
+    auction_id UUID NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    change VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per event and sink so each sink is retried on its own.
+CREATE TABLE domain_event_deliveries (
+    event_id UUID NOT NULL REFERENCES domain_events(id) ON DELETE CASCADE,
+    sink VARCHAR(20) NOT NULL CHECK (sink IN ('HUB', 'WEBHOOK', 'LOG')),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'DELIVERED', 'FAILED')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    published_at TIMESTAMP,
+    PRIMARY KEY (event_id, sink)
+);
+
+CREATE INDEX idx_domain_event_deliveries_due ON domain_event_deliveries(next_attempt_at) WHERE status = 'PENDING';
@@ -0,0 +1,10 @@
+-- Hub messages too large for a NOTIFY payload, which carries the row's id
+-- instead. Rows are read right after they are written and pruned soon after,
+-- so they are not worth logging.
+CREATE UNLOGGED TABLE hub_messages (
+    id UUID PRIMARY KEY,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_hub_messages_created_at ON hub_messages(created_at);
@@ -0,0 +1,11 @@
+-- Which node leads each scheduled job. The lead itself is a Postgres
+-- advisory lock on lock_key, held by the node's own connection so it passes
+-- to another node as soon as that connection goes away; this table only
+-- records who took it and when they last ran the job.
+CREATE TABLE worker_leases (
+    job VARCHAR(100) PRIMARY KEY,
+    lock_key BIGINT NOT NULL,
+    node_id VARCHAR(255) NOT NULL,
+    acquired_at TIMESTAMP NOT NULL,
+    heartbeat_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,4 @@
+ALTER TABLE domain_events DROP CONSTRAINT IF EXISTS domain_events_auction_seq_key;
+ALTER TABLE domain_events DROP COLUMN IF EXISTS bid_ids;
+ALTER TABLE domain_events DROP COLUMN IF EXISTS auction_seq;
+ALTER TABLE auctions DROP COLUMN IF EXISTS event_seq;
@@ -0,0 +1,25 @@
+-- Numbers each auction's domain events 1, 2, 3... so websocket subscribers
+-- can tell when they missed one. auctions.event_seq is the last number
+-- handed out; bumping it in the recording transaction keeps the numbers
+-- gapless per auction. bid_ids lists the bids a new_bid event placed.
+ALTER TABLE auctions ADD COLUMN event_seq BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE domain_events ADD COLUMN auction_seq BIGINT;
+ALTER TABLE domain_events ADD COLUMN bid_ids UUID[] NOT NULL DEFAULT '{}';
+
+UPDATE domain_events e SET auction_seq = n.auction_seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY auction_id ORDER BY seq) AS auction_seq
+    FROM domain_events
+) n
+WHERE e.id = n.id;
+
+UPDATE auctions a SET event_seq = e.last_seq
+FROM (
+    SELECT auction_id, MAX(auction_seq) AS last_seq
+    FROM domain_events
+    GROUP BY auction_id
+) e
+WHERE a.id = e.auction_id;
+
+ALTER TABLE domain_events ALTER COLUMN auction_seq SET NOT NULL;
+ALTER TABLE domain_events ADD CONSTRAINT domain_events_auction_seq_key UNIQUE (auction_id, auction_seq);
@@ -0,0 +1,4 @@
+UPDATE auction_results SET payment_status = 'CAPTURED' WHERE payment_status = 'REFUND_PENDING';
+ALTER TABLE auction_results DROP CONSTRAINT IF EXISTS auction_results_payment_status_check;
+ALTER TABLE auction_results ADD CONSTRAINT auction_results_payment_status_check
+    CHECK (payment_status IN ('PENDING', 'AUTHORIZED', 'CAPTURED', 'FAILED', 'REFUNDED'));
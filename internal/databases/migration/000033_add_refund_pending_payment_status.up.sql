@@ -0,0 +1,5 @@
+-- A refund is recorded as REFUND_PENDING before the provider is asked for
+-- it, so the row never reads CAPTURED after the money has gone back.
+ALTER TABLE auction_results DROP CONSTRAINT IF EXISTS auction_results_payment_status_check;
+ALTER TABLE auction_results ADD CONSTRAINT auction_results_payment_status_check
+    CHECK (payment_status IN ('PENDING', 'AUTHORIZED', 'CAPTURED', 'FAILED', 'REFUND_PENDING', 'REFUNDED'));
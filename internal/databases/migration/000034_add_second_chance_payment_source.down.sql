@@ -0,0 +1,4 @@
+DELETE FROM payment_status_transitions WHERE source = 'SECOND_CHANCE';
+ALTER TABLE payment_status_transitions DROP CONSTRAINT IF EXISTS payment_status_transitions_source_check;
+ALTER TABLE payment_status_transitions ADD CONSTRAINT payment_status_transitions_source_check
+    CHECK (source IN ('CHECKOUT', 'WEBHOOK', 'ADMIN'));
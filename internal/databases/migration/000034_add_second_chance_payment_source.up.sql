@@ -0,0 +1,5 @@
+-- Accepting a second-chance offer starts the payment over for the new
+-- winner; the reset is recorded like any other payment status change.
+ALTER TABLE payment_status_transitions DROP CONSTRAINT IF EXISTS payment_status_transitions_source_check;
+ALTER TABLE payment_status_transitions ADD CONSTRAINT payment_status_transitions_source_check
+    CHECK (source IN ('CHECKOUT', 'WEBHOOK', 'ADMIN', 'SECOND_CHANCE'));
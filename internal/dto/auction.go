@@ -10,48 +10,100 @@ import (
 )
 
 type CreateAuctionRequest struct {
-	ItemID        uuid.UUID `json:"item_id"`
-	Description   string    `json:"description"`
-	StartingPrice float64   `json:"starting_price"`
-	StartTime     time.Time `json:"start_time"`
-	EndTime       time.Time `json:"end_time"`
-	Status        string    `json:"status"`
+	ItemID          uuid.UUID             `json:"item_id"`
+	Description     string                `json:"description"`
+	StartingPrice   float64               `json:"starting_price"`
+	StartTime       time.Time             `json:"start_time"`
+	EndTime         time.Time             `json:"end_time"`
+	Status          string                `json:"status"`
+	BidIncrement    *models.BidIncrement  `json:"bid_increment"`
+	SoftClose       *models.SoftClose     `json:"soft_close"`
+	ReservePrice    *float64              `json:"reserve_price"`
+	BuyNowPrice     *float64              `json:"buy_now_price"`
+	AuctionType     string                `json:"auction_type"`
+	DutchSchedule   *models.DutchSchedule `json:"dutch_schedule"`
+	Quantity        int                   `json:"quantity"`
+	DepositRequired bool                  `json:"deposit_required"`
+	// EventID and LotNumber are set when the auction is created as a lot of
+	// an event, never by the client.
+	EventID   *uuid.UUID `json:"-"`
+	LotNumber *int       `json:"-"`
 }
 
 type UpdateAuctionRequest struct {
-	StartingPrice float64   `json:"starting_price"`
-	StartTime     time.Time `json:"start_time"`
-	EndTime       time.Time `json:"end_time"`
-	Status        *string   `json:"status"`
+	StartingPrice float64               `json:"starting_price"`
+	StartTime     time.Time             `json:"start_time"`
+	EndTime       time.Time             `json:"end_time"`
+	Status        *string               `json:"status"`
+	BidIncrement  *models.BidIncrement  `json:"bid_increment"`
+	SoftClose     *models.SoftClose     `json:"soft_close"`
+	ReservePrice  *float64              `json:"reserve_price"`
+	BuyNowPrice   *float64              `json:"buy_now_price"`
+	DutchSchedule *models.DutchSchedule `json:"dutch_schedule"`
 }
 
 type ResponseAuction struct {
-	ID              uuid.UUID          `json:"id"`
-	Description     *string            `json:"description"`
-	CreatedBy       uuid.UUID          `json:"created_by"`
-	User            UserDetailResponse `json:"user"`
-	ItemID          uuid.UUID          `json:"item_id"`
-	Item            *ItemResponse      `json:"item,omitempty"`
-	StartingPrice   float64            `json:"starting_price"`
-	CurrentPrice    float64            `json:"current_price"`
-	StartTime       time.Time          `json:"start_time"`
-	EndTime         time.Time          `json:"end_time"`
-	CurrentBidderID *uuid.UUID         `json:"current_bidder_id" db:"current_bidder_id"`
-	Status          string             `json:"status"`
-	CreatedAt       string             `json:"created_at"`
-	UpdatedAt       string             `json:"updated_at"`
+	ID              uuid.UUID             `json:"id"`
+	Description     *string               `json:"description"`
+	CreatedBy       uuid.UUID             `json:"created_by"`
+	User            UserDetailResponse    `json:"user"`
+	ItemID          uuid.UUID             `json:"item_id"`
+	Item            *ItemResponse         `json:"item,omitempty"`
+	StartingPrice   float64               `json:"starting_price"`
+	CurrentPrice    float64               `json:"current_price"`
+	StartTime       time.Time             `json:"start_time"`
+	EndTime         time.Time             `json:"end_time"`
+	CurrentBidderID *uuid.UUID            `json:"current_bidder_id" db:"current_bidder_id"`
+	Status          string                `json:"status"`
+	BidIncrement    models.BidIncrement   `json:"bid_increment"`
+	SoftClose       *models.SoftClose     `json:"soft_close,omitempty"`
+	ReserveMet      bool                  `json:"reserve_met"`
+	Outcome         *string               `json:"outcome,omitempty"`
+	WinnerID        *uuid.UUID            `json:"winner_id,omitempty"`
+	BuyNowPrice     *float64              `json:"buy_now_price,omitempty"`
+	AuctionType     string                `json:"auction_type"`
+	DutchSchedule   *models.DutchSchedule `json:"dutch_schedule,omitempty"`
+	NextPriceDropAt *time.Time            `json:"next_price_drop_at,omitempty"`
+	EventID         *uuid.UUID            `json:"event_id,omitempty"`
+	LotNumber       *int                  `json:"lot_number,omitempty"`
+	Quantity        int                   `json:"quantity"`
+	UnitsDemanded   int                   `json:"units_demanded"`
+	DepositRequired bool                  `json:"deposit_required"`
+	MinNextBid      float64               `json:"min_next_bid"`
+	Seq             int64                 `json:"seq"`
+	CreatedAt       string                `json:"created_at"`
+	UpdatedAt       string                `json:"updated_at"`
+}
+
+// ResolveImageURLs turns the item's stored image paths into absolute URLs
+// under baseURL.
+func (r *ResponseAuction) ResolveImageURLs(baseURL string) {
+	if r.Item == nil {
+		return
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	for i := range r.Item.Images {
+		path := strings.TrimPrefix(r.Item.Images[i].URL, "/")
+		r.Item.Images[i].URL = baseURL + "/" + path
+	}
 }
 
 type ResponseCurrentPrice struct {
 	Amount float64 `json:"amount"`
 }
 
+type ResponseOutbid struct {
+	CurrentPrice float64 `json:"current_price"`
+	MinNextBid   float64 `json:"min_next_bid"`
+}
+
 type FilterAuction struct {
 	Limit         int        `json:"limit"`
 	Status        *string    `json:"status"`
 	StartTime     *time.Time `json:"start_time"`
 	EndTime       *time.Time `json:"end_time"`
 	StartingPrice *float64   `json:"starting_price"`
+	EventID       *uuid.UUID `json:"event_id"`
 }
 
 func IsValidAuctionStatus(status string) bool {
@@ -73,6 +125,69 @@ var validStatuses = []string{
 	string(models.AuctionCancelled),
 }
 
+func IsValidAuctionType(auctionType string) bool {
+	switch auctionType {
+	case string(models.AuctionEnglish),
+		string(models.AuctionDutch),
+		string(models.AuctionSealedFirstPrice),
+		string(models.AuctionSealedSecondPrice):
+		return true
+	default:
+		return false
+	}
+}
+
+var validAuctionTypes = []string{
+	string(models.AuctionEnglish),
+	string(models.AuctionDutch),
+	string(models.AuctionSealedFirstPrice),
+	string(models.AuctionSealedSecondPrice),
+}
+
+// ValidateAuctionTypeRules checks the pricing options that depend on the
+// auction type. An empty type is treated as ENGLISH.
+func ValidateAuctionTypeRules(auctionType models.AuctionType, startingPrice float64, dutch *models.DutchSchedule, reserve, buyNow *float64, softClose *models.SoftClose) error {
+	if auctionType != models.AuctionDutch && dutch != nil {
+		return errors.New("dutch schedule is only allowed on dutch auctions")
+	}
+
+	switch {
+	case auctionType == models.AuctionDutch:
+		if dutch == nil {
+			return errors.New("dutch schedule is required for dutch auctions")
+		}
+		if err := dutch.Validate(startingPrice); err != nil {
+			return err
+		}
+		if reserve != nil || buyNow != nil || softClose != nil {
+			return errors.New("dutch auctions do not support reserve, buy now or soft close")
+		}
+	case auctionType.IsSealed():
+		if buyNow != nil || softClose != nil {
+			return errors.New("sealed-bid auctions do not support buy now or soft close")
+		}
+	}
+	return nil
+}
+
+// ValidateQuantityRules checks the options of an auction selling more than
+// one unit, which clears at a uniform price in an open ascending auction.
+func ValidateQuantityRules(quantity int, auctionType models.AuctionType, buyNow *float64) error {
+	if quantity < 0 {
+		return errors.New("quantity must be greater than 0")
+	}
+	if quantity <= 1 {
+		return nil
+	}
+	if auctionType != "" && auctionType != models.AuctionEnglish {
+		return errors.New("multi-quantity auctions must be english auctions")
+	}
+	if buyNow != nil {
+		return errors.New("multi-quantity auctions do not support buy now")
+	}
+	return nil
+}
+
 func (r *CreateAuctionRequest) Validate() error {
 	if r.ItemID == uuid.Nil {
 		return errors.New("item ID is required")
@@ -92,6 +207,40 @@ func (r *CreateAuctionRequest) Validate() error {
 	if !IsValidAuctionStatus(r.Status) {
 		return errors.New("status is invalid, must be one of: " + strings.Join(validStatuses, ", "))
 	}
+	// ENDED and CANCELLED are only reached through the state machine.
+	if r.Status != string(models.AuctionScheduled) && r.Status != string(models.AuctionActive) {
+		return errors.New("status is invalid, a new auction must be SCHEDULED or ACTIVE")
+	}
+	if r.BidIncrement != nil {
+		if err := r.BidIncrement.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.SoftClose != nil {
+		if err := r.SoftClose.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.ReservePrice != nil && *r.ReservePrice < r.StartingPrice {
+		return errors.New("reserve price must be greater than or equal to starting price")
+	}
+	if r.BuyNowPrice != nil {
+		if *r.BuyNowPrice <= r.StartingPrice {
+			return errors.New("buy now price must be greater than starting price")
+		}
+		if r.ReservePrice != nil && *r.BuyNowPrice < *r.ReservePrice {
+			return errors.New("buy now price must be greater than or equal to reserve price")
+		}
+	}
+	if r.AuctionType != "" && !IsValidAuctionType(r.AuctionType) {
+		return errors.New("auction type is invalid, must be one of: " + strings.Join(validAuctionTypes, ", "))
+	}
+	if err := ValidateAuctionTypeRules(models.AuctionType(r.AuctionType), r.StartingPrice, r.DutchSchedule, r.ReservePrice, r.BuyNowPrice, r.SoftClose); err != nil {
+		return err
+	}
+	if err := ValidateQuantityRules(r.Quantity, models.AuctionType(r.AuctionType), r.BuyNowPrice); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -106,5 +255,31 @@ func (r *UpdateAuctionRequest) Validate() error {
 	if r.Status != nil && !IsValidAuctionStatus(*r.Status) {
 		return errors.New("status is invalid, must be one of: " + strings.Join(validStatuses, ", "))
 	}
+	if r.BidIncrement != nil {
+		if err := r.BidIncrement.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.SoftClose != nil {
+		if err := r.SoftClose.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.ReservePrice != nil && *r.ReservePrice < r.StartingPrice {
+		return errors.New("reserve price must be greater than or equal to starting price")
+	}
+	if r.BuyNowPrice != nil {
+		if *r.BuyNowPrice <= r.StartingPrice {
+			return errors.New("buy now price must be greater than starting price")
+		}
+		if r.ReservePrice != nil && *r.BuyNowPrice < *r.ReservePrice {
+			return errors.New("buy now price must be greater than or equal to reserve price")
+		}
+	}
+	if r.DutchSchedule != nil {
+		if err := r.DutchSchedule.Validate(r.StartingPrice); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,116 @@
+package dto
+
+import (
+	"errors"
+	"rebid/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateAuctionEventRequest struct {
+	Name                string    `json:"name"`
+	Description         *string   `json:"description"`
+	StartTime           time.Time `json:"start_time"`
+	FirstLotEndTime     time.Time `json:"first_lot_end_time"`
+	CloseStaggerSeconds int       `json:"close_stagger_seconds"`
+}
+
+// CreateLotRequest is an auction created inside an event. Its start and end
+// come from the event and its lot number.
+type CreateLotRequest struct {
+	ItemID          uuid.UUID             `json:"item_id"`
+	Description     string                `json:"description"`
+	StartingPrice   float64               `json:"starting_price"`
+	BidIncrement    *models.BidIncrement  `json:"bid_increment"`
+	SoftClose       *models.SoftClose     `json:"soft_close"`
+	ReservePrice    *float64              `json:"reserve_price"`
+	BuyNowPrice     *float64              `json:"buy_now_price"`
+	AuctionType     string                `json:"auction_type"`
+	DutchSchedule   *models.DutchSchedule `json:"dutch_schedule"`
+	Quantity        int                   `json:"quantity"`
+	DepositRequired bool                  `json:"deposit_required"`
+}
+
+type ResponseAuctionEvent struct {
+	ID                  uuid.UUID           `json:"id"`
+	Name                string              `json:"name"`
+	Description         *string             `json:"description"`
+	CreatedBy           uuid.UUID           `json:"created_by"`
+	User                *UserDetailResponse `json:"user,omitempty"`
+	StartTime           time.Time           `json:"start_time"`
+	FirstLotEndTime     time.Time           `json:"first_lot_end_time"`
+	CloseStaggerSeconds int                 `json:"close_stagger_seconds"`
+	Status              string              `json:"status"`
+	LotCount            int                 `json:"lot_count"`
+	Lots                []ResponseAuction   `json:"lots,omitempty"`
+	CreatedAt           string              `json:"created_at"`
+	UpdatedAt           string              `json:"updated_at"`
+}
+
+type FilterAuctionEvent struct {
+	Limit     int        `json:"limit"`
+	Status    *string    `json:"status"`
+	CreatedBy *uuid.UUID `json:"created_by"`
+	StartTime *time.Time `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+}
+
+func (r *CreateAuctionEventRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if r.StartTime.IsZero() {
+		return errors.New("start time is required")
+	}
+	if r.FirstLotEndTime.IsZero() {
+		return errors.New("first lot end time is required")
+	}
+	if !r.FirstLotEndTime.After(r.StartTime) {
+		return errors.New("first lot end time must be after start time")
+	}
+	if r.CloseStaggerSeconds < 0 {
+		return errors.New("close stagger cannot be negative")
+	}
+	return nil
+}
+
+func NewResponseAuctionEvent(event *models.AuctionEvent, status string, lots []ResponseAuction) *ResponseAuctionEvent {
+	return &ResponseAuctionEvent{
+		ID:                  event.ID,
+		Name:                event.Name,
+		Description:         event.Description,
+		CreatedBy:           event.CreatedBy,
+		StartTime:           event.StartTime,
+		FirstLotEndTime:     event.FirstLotEndTime,
+		CloseStaggerSeconds: event.CloseStaggerSeconds,
+		Status:              status,
+		LotCount:            len(lots),
+		Lots:                lots,
+		CreatedAt:           event.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           event.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToAuctionRequest turns the lot into the auction it creates as lot
+// lotNumber of event, scheduled by the event.
+func (r *CreateLotRequest) ToAuctionRequest(event *models.AuctionEvent, lotNumber int) *CreateAuctionRequest {
+	return &CreateAuctionRequest{
+		ItemID:          r.ItemID,
+		Description:     r.Description,
+		StartingPrice:   r.StartingPrice,
+		StartTime:       event.StartTime,
+		EndTime:         event.LotEndTime(lotNumber),
+		Status:          string(models.AuctionScheduled),
+		BidIncrement:    r.BidIncrement,
+		SoftClose:       r.SoftClose,
+		ReservePrice:    r.ReservePrice,
+		BuyNowPrice:     r.BuyNowPrice,
+		AuctionType:     r.AuctionType,
+		DutchSchedule:   r.DutchSchedule,
+		Quantity:        r.Quantity,
+		DepositRequired: r.DepositRequired,
+		EventID:         &event.ID,
+		LotNumber:       &lotNumber,
+	}
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+type ResponseAuctionResult struct {
+	ID            uuid.UUID  `json:"id"`
+	AuctionID     uuid.UUID  `json:"auction_id"`
+	Description   *string    `json:"description"`
+	SellerID      uuid.UUID  `json:"seller_id"`
+	WinnerID      *uuid.UUID `json:"winner_id,omitempty"`
+	Outcome       string     `json:"outcome"`
+	HammerPrice   *float64   `json:"hammer_price,omitempty"`
+	Quantity      int        `json:"quantity"`
+	BuyerFee      float64    `json:"buyer_fee"`
+	SellerFee     float64    `json:"seller_fee"`
+	BuyerTotal    float64    `json:"buyer_total"`
+	SellerPayout  float64    `json:"seller_payout"`
+	PaymentStatus *string    `json:"payment_status,omitempty"`
+	ClosedAt      string     `json:"closed_at"`
+}
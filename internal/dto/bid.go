@@ -2,6 +2,8 @@ package dto
 
 import (
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -9,6 +11,9 @@ import (
 type CreateBidRequest struct {
 	AuctionID uuid.UUID `json:"auction_id"`
 	Amount    float64   `json:"amount"`
+	MaxAmount *float64  `json:"max_amount,omitempty"`
+	// Quantity is the number of units wanted at Amount each. Zero means one.
+	Quantity int `json:"quantity,omitempty"`
 }
 
 type ResponseBid struct {
@@ -16,12 +21,60 @@ type ResponseBid struct {
 	AuctionID uuid.UUID `json:"auction_id"`
 	UserID    uuid.UUID `json:"user_id"`
 	Amount    float64   `json:"amount"`
+	Quantity  int       `json:"quantity"`
+	IsAuto    bool      `json:"is_auto"`
+	Status    string    `json:"status"`
 	CreatedAt string    `json:"created_at"`
 }
 
+type ResponseCreateBid struct {
+	ResponseBid
+	AuctionExtended bool      `json:"auction_extended"`
+	AuctionEnded    bool      `json:"auction_ended"`
+	EndTime         time.Time `json:"end_time"`
+}
+
 type ResponseBidWithUser struct {
 	ResponseBid
-	User      UserDetailResponse `json:"user"`
+	User UserDetailResponse `json:"user"`
+	// InTheMoney marks the bids that would win units if the auction closed
+	// now; UnitsWon is how many, which can be fewer than asked at the margin.
+	InTheMoney bool `json:"in_the_money"`
+	UnitsWon   int  `json:"units_won"`
+}
+
+// SealBids hides the amount and bidder of every bid, keeping only when each
+// was placed, for sealed-bid auctions that are still running.
+func SealBids(bids []ResponseBidWithUser) []ResponseBidWithUser {
+	sealed := make([]ResponseBidWithUser, len(bids))
+	for i, bid := range bids {
+		sealed[i] = ResponseBidWithUser{
+			ResponseBid: ResponseBid{
+				ID:        bid.ID,
+				AuctionID: bid.AuctionID,
+				Status:    bid.Status,
+				CreatedAt: bid.CreatedAt,
+			},
+		}
+	}
+	return sealed
+}
+
+type CancelBidRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ResponseVoidBid struct {
+	ResponseBid
+	CurrentPrice    float64    `json:"current_price"`
+	CurrentBidderID *uuid.UUID `json:"current_bidder_id"`
+}
+
+func (r *CancelBidRequest) Validate() error {
+	if strings.TrimSpace(r.Reason) == "" {
+		return errors.New("reason is required")
+	}
+	return nil
 }
 
 func (r *CreateBidRequest) Validate() error {
@@ -31,5 +84,11 @@ func (r *CreateBidRequest) Validate() error {
 	if r.Amount <= 0 {
 		return errors.New("amount must be greater than 0")
 	}
+	if r.MaxAmount != nil && *r.MaxAmount < r.Amount {
+		return errors.New("max amount must be greater than or equal to amount")
+	}
+	if r.Quantity < 0 {
+		return errors.New("quantity must be greater than 0")
+	}
 	return nil
 }
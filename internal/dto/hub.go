@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/google/uuid"
+
+// ResponseHubRoom is one websocket room on a node. Dropped counts messages
+// its slow consumers never got and Disconnected how many were cut off.
+type ResponseHubRoom struct {
+	Room         string    `json:"room"`
+	ID           uuid.UUID `json:"id"`
+	Clients      int       `json:"clients"`
+	Dropped      int64     `json:"dropped"`
+	Disconnected int64     `json:"disconnected"`
+}
+
+// ResponseHubMetrics is a node's open websocket rooms. Its totals also count
+// rooms that have closed since the node started.
+type ResponseHubMetrics struct {
+	NodeID       string            `json:"node_id"`
+	Clients      int               `json:"clients"`
+	Dropped      int64             `json:"dropped"`
+	Disconnected int64             `json:"disconnected"`
+	Rooms        []ResponseHubRoom `json:"rooms"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"rebid/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FilterNotification struct {
+	Limit  int  `json:"limit"`
+	Unread bool `json:"unread"`
+}
+
+type ResponseNotification struct {
+	ID        uuid.UUID  `json:"id"`
+	Kind      string     `json:"kind"`
+	Subject   string     `json:"subject"`
+	Body      string     `json:"body"`
+	AuctionID *uuid.UUID `json:"auction_id,omitempty"`
+	Read      bool       `json:"read"`
+	ReadAt    *string    `json:"read_at,omitempty"`
+	CreatedAt string     `json:"created_at"`
+}
+
+type ResponseUnreadCount struct {
+	Unread int `json:"unread"`
+}
+
+func NewResponseNotification(n *models.Notification) *ResponseNotification {
+	response := &ResponseNotification{
+		ID:        n.ID,
+		Kind:      n.Kind,
+		Subject:   n.Subject,
+		Body:      n.Body,
+		AuctionID: n.AuctionID,
+		Read:      n.ReadAt != nil,
+		CreatedAt: n.CreatedAt.Format(time.RFC3339),
+	}
+	if n.ReadAt != nil {
+		readAt := n.ReadAt.Format(time.RFC3339)
+		response.ReadAt = &readAt
+	}
+	return response
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type CheckoutRequest struct {
+	PaymentMethod string `json:"payment_method"`
+}
+
+type RefundPaymentRequest struct {
+	Reason *string `json:"reason,omitempty"`
+}
+
+type ResponseCheckout struct {
+	ResultID      uuid.UUID `json:"result_id"`
+	AuctionID     uuid.UUID `json:"auction_id"`
+	Amount        float64   `json:"amount"`
+	PaymentStatus string    `json:"payment_status"`
+	ProviderRef   *string   `json:"provider_ref,omitempty"`
+}
+
+type ResponsePaymentTransition struct {
+	ID          uuid.UUID `json:"id"`
+	FromStatus  string    `json:"from_status"`
+	ToStatus    string    `json:"to_status"`
+	Source      string    `json:"source"`
+	ProviderRef *string   `json:"provider_ref,omitempty"`
+	Amount      *float64  `json:"amount,omitempty"`
+	Detail      *string   `json:"detail,omitempty"`
+	CreatedAt   string    `json:"created_at"`
+}
+
+func (r *CheckoutRequest) Validate() error {
+	if r.PaymentMethod == "" {
+		return errors.New("payment method is required")
+	}
+	return nil
+}
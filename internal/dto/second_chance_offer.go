@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+type ResponseSecondChanceOffer struct {
+	ID               uuid.UUID  `json:"id"`
+	AuctionID        uuid.UUID  `json:"auction_id"`
+	Description      *string    `json:"description"`
+	UserID           uuid.UUID  `json:"user_id"`
+	PreviousWinnerID *uuid.UUID `json:"previous_winner_id,omitempty"`
+	Amount           float64    `json:"amount"`
+	Status           string     `json:"status"`
+	Source           string     `json:"source"`
+	ExpiresAt        string     `json:"expires_at"`
+	RespondedAt      *string    `json:"responded_at,omitempty"`
+	CreatedAt        string     `json:"created_at"`
+}
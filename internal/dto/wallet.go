@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type GrantCreditRequest struct {
+	UserID uuid.UUID `json:"user_id"`
+	Amount float64   `json:"amount"`
+	Memo   *string   `json:"memo,omitempty"`
+}
+
+type ResponseWallet struct {
+	UserID    uuid.UUID            `json:"user_id"`
+	Available float64              `json:"available"`
+	Held      float64              `json:"held"`
+	Holds     []ResponseCreditHold `json:"holds"`
+}
+
+type ResponseCreditHold struct {
+	AuctionID   uuid.UUID `json:"auction_id"`
+	Description *string   `json:"description"`
+	Amount      float64   `json:"amount"`
+	UpdatedAt   string    `json:"updated_at"`
+}
+
+// ResponseLedgerEntry is one side of a ledger transaction as seen from the
+// user's own accounts: a positive amount moved money into Account.
+type ResponseLedgerEntry struct {
+	ID            uuid.UUID  `json:"id"`
+	TransactionID uuid.UUID  `json:"transaction_id"`
+	Kind          string     `json:"kind"`
+	Account       string     `json:"account"`
+	Amount        float64    `json:"amount"`
+	AuctionID     *uuid.UUID `json:"auction_id,omitempty"`
+	Memo          *string    `json:"memo,omitempty"`
+	CreatedAt     string     `json:"created_at"`
+}
+
+func (r *GrantCreditRequest) Validate() error {
+	if r.UserID == uuid.Nil {
+		return errors.New("user ID is required")
+	}
+	if r.Amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WatchAuctionRequest struct {
+	AuctionID uuid.UUID `json:"auction_id"`
+}
+
+type ResponseWatchlistItem struct {
+	AuctionID    uuid.UUID `json:"auction_id"`
+	Description  *string   `json:"description"`
+	Status       string    `json:"status"`
+	CurrentPrice float64   `json:"current_price"`
+	EndTime      time.Time `json:"end_time"`
+	WatchedAt    string    `json:"watched_at"`
+}
+
+func (r *WatchAuctionRequest) Validate() error {
+	if r.AuctionID == uuid.Nil {
+		return errors.New("auction ID is required")
+	}
+	return nil
+}
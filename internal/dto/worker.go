@@ -0,0 +1,17 @@
+package dto
+
+type ResponseWorkerLease struct {
+	Job         string `json:"job"`
+	NodeID      string `json:"node_id"`
+	Active      bool   `json:"active"`
+	AcquiredAt  string `json:"acquired_at"`
+	HeartbeatAt string `json:"heartbeat_at"`
+}
+
+// ResponseWorkerStatus lists which node leads each scheduled job. A lease
+// is active while its node still holds the job's lock; an inactive one is
+// taken over by the next node whose schedule fires.
+type ResponseWorkerStatus struct {
+	NodeID string                `json:"node_id"`
+	Jobs   []ResponseWorkerLease `json:"jobs"`
+}
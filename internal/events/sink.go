@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sinks a domain event can be published to.
+const (
+	SinkHub     = "HUB"
+	SinkWebhook = "WEBHOOK"
+	SinkLog     = "LOG"
+)
+
+// Event is a change to an auction, published at least once to every sink.
+// ID stays the same across redeliveries so receivers can drop duplicates,
+// and Seq orders events that happened in the same instant. AuctionSeq
+// numbers the auction's own events without gaps, and BidIDs lists the bids
+// a new_bid event placed.
+type Event struct {
+	ID         uuid.UUID
+	Seq        int64
+	AuctionID  uuid.UUID
+	AuctionSeq int64
+	Change     string
+	BidIDs     []uuid.UUID
+	CreatedAt  time.Time
+}
+
+// Sink publishes events outside the database.
+type Sink interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// LogSink writes every event to the log, for development.
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Publish(ctx context.Context, e Event) error {
+	log.Printf("event %d %s: auction %s %s", e.Seq, e.ID, e.AuctionID, e.Change)
+	return nil
+}
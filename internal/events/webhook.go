@@ -0,0 +1,68 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSink posts every event as JSON to a fixed URL, with its ID in the
+// Idempotency-Key header. Any non-2xx response counts as a failed delivery.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type webhookPayload struct {
+	ID         uuid.UUID   `json:"id"`
+	Seq        int64       `json:"seq"`
+	AuctionID  uuid.UUID   `json:"auction_id"`
+	AuctionSeq int64       `json:"auction_seq"`
+	Change     string      `json:"change"`
+	BidIDs     []uuid.UUID `json:"bid_ids"`
+	CreatedAt  string      `json:"created_at"`
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, e Event) error {
+	b, err := json.Marshal(webhookPayload{
+		ID:         e.ID,
+		Seq:        e.Seq,
+		AuctionID:  e.AuctionID,
+		AuctionSeq: e.AuctionSeq,
+		Change:     e.Change,
+		BidIDs:     e.BidIDs,
+		CreatedAt:  e.CreatedAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", e.ID.String())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("post webhook: unexpected status %s", resp.Status)
+	}
+	return nil
+}
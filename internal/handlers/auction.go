@@ -8,6 +8,8 @@ import (
 	"rebid/pkg"
 	"strconv"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 func (h *Handler) AuctionHandler(w http.ResponseWriter, r *http.Request) {
@@ -38,6 +40,24 @@ func (h *Handler) AuctionByIDHandler(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) GetAllAuctions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+
+	filter, ok := parseAuctionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	auctions, err := h.auctionService.GetAllAuctions(ctx, filter)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Auctions retrieved successfully", auctions))
+}
+
+// parseAuctionFilter reads the auction list filters from the query string,
+// writing a 400 and returning false when one is malformed.
+func parseAuctionFilter(w http.ResponseWriter, r *http.Request) (*dto.FilterAuction, bool) {
 	query := r.URL.Query()
 
 	filter := &dto.FilterAuction{}
@@ -47,7 +67,7 @@ func (h *Handler) GetAllAuctions(w http.ResponseWriter, r *http.Request) {
 		l, err := strconv.Atoi(limit)
 		if err != nil {
 			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid limit"))
-			return
+			return nil, false
 		}
 		filter.Limit = l
 	}
@@ -62,7 +82,7 @@ func (h *Handler) GetAllAuctions(w http.ResponseWriter, r *http.Request) {
 		price, err := strconv.ParseFloat(sp, 64)
 		if err != nil {
 			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid starting_price"))
-			return
+			return nil, false
 		}
 		filter.StartingPrice = &price
 	}
@@ -72,7 +92,7 @@ func (h *Handler) GetAllAuctions(w http.ResponseWriter, r *http.Request) {
 		t, err := time.Parse(time.RFC3339, st)
 		if err != nil {
 			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid start_time"))
-			return
+			return nil, false
 		}
 		filter.StartTime = &t
 	}
@@ -82,18 +102,22 @@ func (h *Handler) GetAllAuctions(w http.ResponseWriter, r *http.Request) {
 		t, err := time.Parse(time.RFC3339, et)
 		if err != nil {
 			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid end_time"))
-			return
+			return nil, false
 		}
 		filter.EndTime = &t
 	}
 
-	auctions, err := h.auctionService.GetAllAuctions(ctx, filter)
-	if err != nil {
-		pkg.HandleServiceError(w, err)
-		return
+	// event_id
+	if ev := query.Get("event_id"); ev != "" {
+		id, err := uuid.Parse(ev)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid event_id"))
+			return nil, false
+		}
+		filter.EventID = &id
 	}
 
-	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Auctions retrieved successfully", auctions))
+	return filter, true
 }
 
 func (h *Handler) CreateAuction(w http.ResponseWriter, r *http.Request) {
@@ -126,6 +150,12 @@ func (h *Handler) CreateAuction(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) UpdateAuction(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
 	auctionID := r.PathValue("id")
 	if auctionID == "" {
 		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Auction ID is required"))
@@ -143,7 +173,7 @@ func (h *Handler) UpdateAuction(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	auction, err := h.auctionService.UpdateAuction(ctx, request, auctionID)
+	auction, err := h.auctionService.UpdateAuction(ctx, request, auctionID, userID, middleware.GetUserRole(r))
 	if err != nil {
 		pkg.HandleServiceError(w, err)
 		return
@@ -152,6 +182,29 @@ func (h *Handler) UpdateAuction(w http.ResponseWriter, r *http.Request) {
 	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Auction updated successfully", auction))
 }
 
+func (h *Handler) BuyNow(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	auctionID := r.PathValue("id")
+	if auctionID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Auction ID is required"))
+		return
+	}
+
+	bid, err := h.auctionService.BuyNow(ctx, auctionID, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusCreated, pkg.SuccessResponse("Auction bought successfully", bid))
+}
+
 func (h *Handler) GetAuctionByID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	auctionID := r.PathValue("id")
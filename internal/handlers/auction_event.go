@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"rebid/internal/dto"
+	"rebid/internal/middleware"
+	"rebid/pkg"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (h *Handler) GetAllAuctionEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query()
+
+	filter := &dto.FilterAuctionEvent{}
+
+	// limit
+	if limit := query.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid limit"))
+			return
+		}
+		filter.Limit = l
+	}
+
+	// status
+	if status := query.Get("status"); status != "" {
+		filter.Status = &status
+	}
+
+	// created_by
+	if cb := query.Get("created_by"); cb != "" {
+		id, err := uuid.Parse(cb)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid created_by"))
+			return
+		}
+		filter.CreatedBy = &id
+	}
+
+	// start_time
+	if st := query.Get("start_time"); st != "" {
+		t, err := time.Parse(time.RFC3339, st)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid start_time"))
+			return
+		}
+		filter.StartTime = &t
+	}
+
+	// end_time
+	if et := query.Get("end_time"); et != "" {
+		t, err := time.Parse(time.RFC3339, et)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid end_time"))
+			return
+		}
+		filter.EndTime = &t
+	}
+
+	events, err := h.eventService.GetAllEvents(ctx, filter)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Auction events retrieved successfully", events))
+}
+
+func (h *Handler) CreateAuctionEvent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	request := &dto.CreateAuctionEventRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Invalid request body"))
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse(err.Error()))
+		return
+	}
+
+	event, err := h.eventService.CreateEvent(ctx, request, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusCreated, pkg.SuccessResponse("Auction event created successfully", event))
+}
+
+func (h *Handler) GetAuctionEventByID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	eventID := r.PathValue("id")
+	if eventID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Event ID is required"))
+		return
+	}
+
+	event, err := h.eventService.GetEventByID(ctx, eventID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Auction event retrieved successfully", event))
+}
+
+func (h *Handler) GetAuctionEventLots(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	eventID := r.PathValue("id")
+	if eventID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Event ID is required"))
+		return
+	}
+
+	filter, ok := parseAuctionFilter(w, r)
+	if !ok {
+		return
+	}
+
+	lots, err := h.eventService.GetEventLots(ctx, eventID, filter)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Lots retrieved successfully", lots))
+}
+
+func (h *Handler) CreateLot(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	eventID := r.PathValue("id")
+	if eventID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Event ID is required"))
+		return
+	}
+
+	request := &dto.CreateLotRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Invalid request body"))
+		return
+	}
+
+	lot, err := h.eventService.AddLot(ctx, eventID, request, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusCreated, pkg.SuccessResponse("Lot created successfully", lot))
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"rebid/internal/middleware"
+	"rebid/pkg"
+)
+
+func (h *Handler) GetAuctionResult(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	auctionID := r.PathValue("id")
+	if auctionID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Auction ID is required"))
+		return
+	}
+
+	result, err := h.auctionService.GetAuctionResult(ctx, auctionID, userID, middleware.GetUserRole(r))
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Auction result retrieved successfully", result))
+}
+
+func (h *Handler) GetWonAuctions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	results, err := h.auctionService.GetWonAuctions(ctx, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Won auctions retrieved successfully", results))
+}
+
+func (h *Handler) GetSoldAuctions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	results, err := h.auctionService.GetSoldAuctions(ctx, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Sold auctions retrieved successfully", results))
+}
@@ -5,7 +5,6 @@ import (
 	"net/http"
 	"rebid/internal/dto"
 	"rebid/internal/middleware"
-	"rebid/internal/websocket"
 	"rebid/pkg"
 )
 
@@ -44,36 +43,62 @@ func (h *Handler) CreateBid(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var auctionID string = bid.AuctionID.String()
-	auction, err := h.auctionService.GetAuctionByID(ctx, auctionID)
+	pkg.JSONResponse(w, http.StatusCreated, pkg.SuccessResponse("Bid created successfully", bid))
+}
+
+func (h *Handler) RetractBid(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	bidID := r.PathValue("id")
+	if bidID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Bid ID is required"))
+		return
+	}
+
+	bid, err := h.bidService.RetractBid(ctx, bidID, userID)
 	if err != nil {
-		pkg.JSONResponse(w, http.StatusInternalServerError, pkg.ErrorResponse("Failed to get auction"))
+		pkg.HandleServiceError(w, err)
 		return
 	}
 
-	bidsWithUser, err := h.bidService.GetListBidByAuctionID(ctx, auctionID)
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Bid retracted successfully", bid))
+}
+
+func (h *Handler) CancelBid(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
 
-	if h.wsHub != nil {
-		// newBidPayload := websocket.NewBidPayload{
-		// 	Event: "new_bid",
-		// 	Bid:   *bid,
-		// }
+	bidID := r.PathValue("id")
+	if bidID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Bid ID is required"))
+		return
+	}
 
-		// b, _ := json.Marshal(newBidPayload)
-		// h.wsHub.BroadcastToAuction(bid.AuctionID, b)
+	request := &dto.CancelBidRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Invalid request body"))
+		return
+	}
 
-		subPayload := websocket.SubscribedPayload{
-			Event:           "auction",
-			Change:          websocket.ChangeNewBid,
-			Auction:         *auction,
-			CurrentPrice:    auction.CurrentPrice,
-			CurrentBidderID: auction.CurrentBidderID,
-			Bids:            bidsWithUser,
-		}
+	if err := request.Validate(); err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse(err.Error()))
+		return
+	}
 
-		b2, _ := json.Marshal(subPayload)
-		h.wsHub.BroadcastToAuction(bid.AuctionID, b2)
+	bid, err := h.bidService.CancelBid(ctx, bidID, userID, middleware.GetUserRole(r), request.Reason)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
 	}
 
-	pkg.JSONResponse(w, http.StatusCreated, pkg.SuccessResponse("Bid created successfully", bid))
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Bid cancelled successfully", bid))
 }
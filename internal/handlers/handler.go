@@ -3,7 +3,6 @@ package handlers
 import (
 	"rebid/internal/config"
 	"rebid/internal/services"
-	"rebid/internal/websocket"
 )
 
 type Handler struct {
@@ -12,16 +11,30 @@ type Handler struct {
 	itemService    *services.ItemService
 	auctionService *services.AuctionService
 	bidService     *services.BidService
-	wsHub          *websocket.Hub
+	eventService   *services.AuctionEventService
+	walletService  *services.WalletService
+	paymentService *services.PaymentService
+	offerService   *services.SecondChanceService
+	watchService   *services.WatchlistService
+	notifyService  *services.NotificationService
+	workerService  *services.WorkerService
+	hubService     *services.HubService
 }
 
 func NewHandler(
 	cfg *config.Config,
-	wsHub *websocket.Hub,
 	userService *services.UserService,
 	itemService *services.ItemService,
 	auctionService *services.AuctionService,
 	bidService *services.BidService,
+	eventService *services.AuctionEventService,
+	walletService *services.WalletService,
+	paymentService *services.PaymentService,
+	offerService *services.SecondChanceService,
+	watchService *services.WatchlistService,
+	notifyService *services.NotificationService,
+	workerService *services.WorkerService,
+	hubService *services.HubService,
 ) *Handler {
 	return &Handler{
 		cfg:            cfg,
@@ -29,6 +42,13 @@ func NewHandler(
 		itemService:    itemService,
 		auctionService: auctionService,
 		bidService:     bidService,
-		wsHub:          wsHub,
+		eventService:   eventService,
+		walletService:  walletService,
+		paymentService: paymentService,
+		offerService:   offerService,
+		watchService:   watchService,
+		notifyService:  notifyService,
+		workerService:  workerService,
+		hubService:     hubService,
 	}
 }
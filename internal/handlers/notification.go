@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+	"rebid/internal/dto"
+	"rebid/internal/middleware"
+	"rebid/pkg"
+	"strconv"
+)
+
+func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	query := r.URL.Query()
+	filter := &dto.FilterNotification{}
+
+	// limit
+	if limit := query.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid limit"))
+			return
+		}
+		filter.Limit = l
+	}
+
+	// unread
+	if unread := query.Get("unread"); unread != "" {
+		u, err := strconv.ParseBool(unread)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid unread"))
+			return
+		}
+		filter.Unread = u
+	}
+
+	notifications, err := h.notifyService.GetNotifications(ctx, userID, filter)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Notifications retrieved successfully", notifications))
+}
+
+func (h *Handler) GetUnreadNotificationCount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	count, err := h.notifyService.GetUnreadCount(ctx, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Unread count retrieved successfully", count))
+}
+
+func (h *Handler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	notificationID := r.PathValue("id")
+	if notificationID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Notification ID is required"))
+		return
+	}
+
+	notification, err := h.notifyService.MarkRead(ctx, notificationID, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Notification marked as read", notification))
+}
+
+func (h *Handler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	count, err := h.notifyService.MarkAllRead(ctx, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("All notifications marked as read", count))
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"rebid/internal/dto"
+	"rebid/internal/middleware"
+	"rebid/pkg"
+)
+
+// maxWebhookBytes bounds the payment webhook body read into memory.
+const maxWebhookBytes = 1 << 20
+
+func (h *Handler) Checkout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	auctionID := r.PathValue("id")
+	if auctionID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Auction ID is required"))
+		return
+	}
+
+	request := &dto.CheckoutRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Invalid request body"))
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse(err.Error()))
+		return
+	}
+
+	checkout, err := h.paymentService.Checkout(ctx, auctionID, request, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Payment captured successfully", checkout))
+}
+
+func (h *Handler) GetPaymentHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	resultID := r.PathValue("id")
+	if resultID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Result ID is required"))
+		return
+	}
+
+	history, err := h.paymentService.GetPaymentHistory(ctx, resultID, userID, middleware.GetUserRole(r))
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Payment history retrieved successfully", history))
+}
+
+func (h *Handler) RefundPayment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	resultID := r.PathValue("id")
+	if resultID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Result ID is required"))
+		return
+	}
+
+	request := &dto.RefundPaymentRequest{}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Invalid request body"))
+			return
+		}
+	}
+
+	refund, err := h.paymentService.RefundPayment(ctx, resultID, request, userID, middleware.GetUserRole(r))
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Payment refunded successfully", refund))
+}
+
+func (h *Handler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBytes))
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Invalid request body"))
+		return
+	}
+
+	if err := h.paymentService.HandleWebhook(r.Context(), payload, r.Header.Get("X-Payment-Signature")); err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Webhook processed successfully", nil))
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"rebid/internal/middleware"
+	"rebid/pkg"
+)
+
+func (h *Handler) CreateSecondChanceOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	auctionID := r.PathValue("id")
+	if auctionID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Auction ID is required"))
+		return
+	}
+
+	offer, err := h.offerService.CreateOffer(ctx, auctionID, userID, middleware.GetUserRole(r))
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusCreated, pkg.SuccessResponse("Second-chance offer created successfully", offer))
+}
+
+func (h *Handler) GetAuctionSecondChanceOffers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	auctionID := r.PathValue("id")
+	if auctionID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Auction ID is required"))
+		return
+	}
+
+	offers, err := h.offerService.GetAuctionOffers(ctx, auctionID, userID, middleware.GetUserRole(r))
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Second-chance offers retrieved successfully", offers))
+}
+
+func (h *Handler) GetMySecondChanceOffers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	offers, err := h.offerService.GetUserOffers(ctx, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Second-chance offers retrieved successfully", offers))
+}
+
+func (h *Handler) AcceptSecondChanceOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	offerID := r.PathValue("id")
+	if offerID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Offer ID is required"))
+		return
+	}
+
+	offer, err := h.offerService.AcceptOffer(ctx, offerID, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Second-chance offer accepted successfully", offer))
+}
+
+func (h *Handler) DeclineSecondChanceOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	offerID := r.PathValue("id")
+	if offerID == "" {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Offer ID is required"))
+		return
+	}
+
+	offer, err := h.offerService.DeclineOffer(ctx, offerID, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Second-chance offer declined successfully", offer))
+}
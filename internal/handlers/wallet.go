@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"rebid/internal/dto"
+	"rebid/internal/middleware"
+	"rebid/pkg"
+	"strconv"
+)
+
+func (h *Handler) GetWallet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	wallet, err := h.walletService.GetWallet(ctx, userID)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Wallet retrieved successfully", wallet))
+}
+
+func (h *Handler) GetWalletEntries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	limit := 0
+	if l := r.URL.Query().Get("limit"); l != "" {
+		limit, err = strconv.Atoi(l)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid limit"))
+			return
+		}
+	}
+
+	entries, err := h.walletService.GetEntries(ctx, userID, limit)
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Wallet entries retrieved successfully", entries))
+}
+
+func (h *Handler) GrantCredit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, err := middleware.GetUserByID(r)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	request := &dto.GrantCreditRequest{}
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("Invalid request body"))
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse(err.Error()))
+		return
+	}
+
+	wallet, err := h.walletService.GrantCredit(ctx, request, userID, middleware.GetUserRole(r))
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusCreated, pkg.SuccessResponse("Credit granted successfully", wallet))
+}
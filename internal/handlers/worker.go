@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+	"rebid/internal/middleware"
+	"rebid/pkg"
+)
+
+func (h *Handler) GetWorkerStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if _, err := middleware.GetUserByID(r); err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("User not authenticated"))
+		return
+	}
+
+	status, err := h.workerService.GetStatus(ctx, middleware.GetUserRole(r))
+	if err != nil {
+		pkg.HandleServiceError(w, err)
+		return
+	}
+
+	pkg.JSONResponse(w, http.StatusOK, pkg.SuccessResponse("Worker status retrieved successfully", status))
+}
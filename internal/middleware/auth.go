@@ -68,3 +68,8 @@ func GetUserByID(r *http.Request) (uuid.UUID, error) {
 	}
 	return userID, nil
 }
+
+func GetUserRole(r *http.Request) string {
+	role, _ := r.Context().Value(RoleKey).(string)
+	return role
+}
@@ -15,17 +15,68 @@ const (
 	AuctionCancelled AuctionStatus = "CANCELLED"
 )
 
+// AuctionType is how an auction's price is discovered.
+type AuctionType string
+
+const (
+	// AuctionEnglish is an ascending auction won by the highest bid at close.
+	AuctionEnglish AuctionType = "ENGLISH"
+	// AuctionDutch starts high and drops on a schedule; the first bidder to
+	// accept the current price wins and ends the auction.
+	AuctionDutch AuctionType = "DUTCH"
+	// AuctionSealedFirstPrice hides bids until close; the highest bidder
+	// pays their own bid.
+	AuctionSealedFirstPrice AuctionType = "SEALED_FIRST_PRICE"
+	// AuctionSealedSecondPrice (Vickrey) hides bids until close; the highest
+	// bidder pays the second-highest bid.
+	AuctionSealedSecondPrice AuctionType = "SEALED_SECOND_PRICE"
+)
+
+func (t AuctionType) IsSealed() bool {
+	return t == AuctionSealedFirstPrice || t == AuctionSealedSecondPrice
+}
+
+// HidesBids reports whether bid amounts and bidders must stay secret for an
+// auction of this type in status.
+func (t AuctionType) HidesBids(status AuctionStatus) bool {
+	return t.IsSealed() && status == AuctionActive
+}
+
+// AuctionOutcome is how an ENDED auction finished.
+type AuctionOutcome string
+
+const (
+	OutcomeSold          AuctionOutcome = "SOLD"
+	OutcomeReserveNotMet AuctionOutcome = "RESERVE_NOT_MET"
+	OutcomeNoBids        AuctionOutcome = "NO_BIDS"
+)
+
 type Auction struct {
-	ID              uuid.UUID     `json:"id" db:"id"`
-	CreatedBy       uuid.UUID     `json:"created_by" db:"created_by"`
-	ItemID          uuid.UUID     `json:"item_id" db:"item_id"`
-	StartingPrice   float64       `json:"starting_price" db:"starting_price"`
-	CurrentPrice    float64       `json:"current_price" db:"current_price"`
-	StartTime       time.Time     `json:"start_time" db:"start_time"`
-	EndTime         time.Time     `json:"end_time" db:"end_time"`
-	CurrentBidderID *uuid.UUID    `json:"current_bidder_id,omitempty" db:"current_bidder_id"`
-	Status          AuctionStatus `json:"status" db:"status"`
-	Description     string        `json:"description" db:"description"`
-	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
-	UpdatedAt       *time.Time    `json:"updated_at,omitempty" db:"updated_at"`
+	ID              uuid.UUID       `json:"id" db:"id"`
+	CreatedBy       uuid.UUID       `json:"created_by" db:"created_by"`
+	ItemID          uuid.UUID       `json:"item_id" db:"item_id"`
+	StartingPrice   float64         `json:"starting_price" db:"starting_price"`
+	CurrentPrice    float64         `json:"current_price" db:"current_price"`
+	StartTime       time.Time       `json:"start_time" db:"start_time"`
+	EndTime         time.Time       `json:"end_time" db:"end_time"`
+	CurrentBidderID *uuid.UUID      `json:"current_bidder_id,omitempty" db:"current_bidder_id"`
+	Status          AuctionStatus   `json:"status" db:"status"`
+	Description     string          `json:"description" db:"description"`
+	AuctionType     AuctionType     `json:"auction_type" db:"auction_type"`
+	DutchSchedule   *DutchSchedule  `json:"dutch_schedule,omitempty" db:"dutch_schedule"`
+	PriceDroppedAt  *time.Time      `json:"price_dropped_at,omitempty" db:"price_dropped_at"`
+	EventID         *uuid.UUID      `json:"event_id,omitempty" db:"event_id"`
+	LotNumber       *int            `json:"lot_number,omitempty" db:"lot_number"`
+	Quantity        int             `json:"quantity" db:"quantity"`
+	UnitsDemanded   int             `json:"units_demanded" db:"units_demanded"`
+	DepositRequired bool            `json:"deposit_required" db:"deposit_required"`
+	BidIncrement    BidIncrement    `json:"bid_increment" db:"bid_increment"`
+	SoftClose       *SoftClose      `json:"soft_close,omitempty" db:"soft_close"`
+	OriginalEndTime time.Time       `json:"original_end_time" db:"original_end_time"`
+	ReservePrice    *float64        `json:"-" db:"reserve_price"`
+	Outcome         *AuctionOutcome `json:"outcome,omitempty" db:"outcome"`
+	WinnerID        *uuid.UUID      `json:"winner_id,omitempty" db:"winner_id"`
+	BuyNowPrice     *float64        `json:"buy_now_price,omitempty" db:"buy_now_price"`
+	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt       *time.Time      `json:"updated_at,omitempty" db:"updated_at"`
 }
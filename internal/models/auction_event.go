@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuctionEvent groups lot-auctions that open together at StartTime and close
+// in sequence: lot n is scheduled to end (n-1) * CloseStaggerSeconds after
+// FirstLotEndTime, and never before the lot ahead of it plus the stagger.
+type AuctionEvent struct {
+	ID                  uuid.UUID `json:"id" db:"id"`
+	Name                string    `json:"name" db:"name"`
+	Description         *string   `json:"description,omitempty" db:"description"`
+	CreatedBy           uuid.UUID `json:"created_by" db:"created_by"`
+	StartTime           time.Time `json:"start_time" db:"start_time"`
+	FirstLotEndTime     time.Time `json:"first_lot_end_time" db:"first_lot_end_time"`
+	CloseStaggerSeconds int       `json:"close_stagger_seconds" db:"close_stagger_seconds"`
+	CreatedAt           time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// LotEndTime is when lot lotNumber is scheduled to close.
+func (e AuctionEvent) LotEndTime(lotNumber int) time.Time {
+	return e.FirstLotEndTime.Add(time.Duration(lotNumber-1) * time.Duration(e.CloseStaggerSeconds) * time.Second)
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"rebid/pkg"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuctionResult is the settlement written when an auction ends. It is the
+// durable record of who won and what is owed, independent of later edits to
+// the auction row.
+type AuctionResult struct {
+	ID          uuid.UUID      `json:"id" db:"id"`
+	AuctionID   uuid.UUID      `json:"auction_id" db:"auction_id"`
+	SellerID    uuid.UUID      `json:"seller_id" db:"seller_id"`
+	WinnerID    *uuid.UUID     `json:"winner_id,omitempty" db:"winner_id"`
+	Outcome     AuctionOutcome `json:"outcome" db:"outcome"`
+	HammerPrice *float64       `json:"hammer_price,omitempty" db:"hammer_price"`
+	Quantity    int            `json:"quantity" db:"quantity"`
+	BuyerFee    float64        `json:"buyer_fee" db:"buyer_fee"`
+	SellerFee   float64        `json:"seller_fee" db:"seller_fee"`
+	// PaymentStatus is set on sold results only.
+	PaymentStatus      *PaymentStatus `json:"payment_status,omitempty" db:"payment_status"`
+	PaymentProviderRef *string        `json:"payment_provider_ref,omitempty" db:"payment_provider_ref"`
+	ClosedAt           time.Time      `json:"closed_at" db:"closed_at"`
+	CreatedAt          time.Time      `json:"created_at" db:"created_at"`
+}
+
+// BuyerTotal is what the winner owes: the hammer price for every unit won
+// plus the buyer's premium.
+func (r *AuctionResult) BuyerTotal() float64 {
+	if r.HammerPrice == nil {
+		return 0
+	}
+	return pkg.RoundPrice(*r.HammerPrice*float64(r.Quantity) + r.BuyerFee)
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuctionActor is who triggered a status transition.
+type AuctionActor string
+
+const (
+	ActorOwner  AuctionActor = "OWNER"
+	ActorAdmin  AuctionActor = "ADMIN"
+	ActorSystem AuctionActor = "SYSTEM"
+)
+
+// AuctionStatusTransition is the audit row written for every status change.
+type AuctionStatusTransition struct {
+	ID         uuid.UUID     `json:"id" db:"id"`
+	AuctionID  uuid.UUID     `json:"auction_id" db:"auction_id"`
+	FromStatus AuctionStatus `json:"from_status" db:"from_status"`
+	ToStatus   AuctionStatus `json:"to_status" db:"to_status"`
+	ActorType  AuctionActor  `json:"actor_type" db:"actor_type"`
+	ActorID    *uuid.UUID    `json:"actor_id,omitempty" db:"actor_id"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+}
@@ -6,10 +6,26 @@ import (
 	"github.com/google/uuid"
 )
 
+// BidStatus tells whether a bid still counts. Void bids are kept for the
+// record but ignored when pricing and settling the auction.
+type BidStatus string
+
+const (
+	BidValid     BidStatus = "VALID"
+	BidRetracted BidStatus = "RETRACTED"
+	BidCancelled BidStatus = "CANCELLED"
+)
+
 type Bid struct {
-	ID        uuid.UUID `json:"id" db:"id"`
-	AuctionID uuid.UUID `json:"auction_id" db:"auction_id"`
-	UserID    uuid.UUID `json:"user_id" db:"user_id"`
-	Amount    float64   `json:"amount" db:"amount"`
-	BidTime   time.Time `json:"bid_time" db:"bid_time"`
+	ID         uuid.UUID  `json:"id" db:"id"`
+	AuctionID  uuid.UUID  `json:"auction_id" db:"auction_id"`
+	UserID     uuid.UUID  `json:"user_id" db:"user_id"`
+	Amount     float64    `json:"amount" db:"amount"`
+	Quantity   int        `json:"quantity" db:"quantity"`
+	IsAuto     bool       `json:"is_auto" db:"is_auto"`
+	Status     BidStatus  `json:"status" db:"status"`
+	VoidedAt   *time.Time `json:"voided_at,omitempty" db:"voided_at"`
+	VoidedBy   *uuid.UUID `json:"voided_by,omitempty" db:"voided_by"`
+	VoidReason *string    `json:"void_reason,omitempty" db:"void_reason"`
+	BidTime    time.Time  `json:"bid_time" db:"bid_time"`
 }
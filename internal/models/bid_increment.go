@@ -0,0 +1,133 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"rebid/pkg"
+)
+
+type IncrementType string
+
+const (
+	IncrementFixed   IncrementType = "FIXED"
+	IncrementPercent IncrementType = "PERCENT"
+	IncrementTiered  IncrementType = "TIERED"
+)
+
+// IncrementTier applies Amount while the current price is in [From, To).
+// A nil To leaves the band open-ended.
+type IncrementTier struct {
+	From   float64  `json:"from"`
+	To     *float64 `json:"to,omitempty"`
+	Amount float64  `json:"amount"`
+}
+
+// BidIncrement is the minimum raise policy of an auction, stored as JSONB.
+type BidIncrement struct {
+	Type    IncrementType   `json:"type"`
+	Amount  float64         `json:"amount,omitempty"`
+	Percent float64         `json:"percent,omitempty"`
+	Tiers   []IncrementTier `json:"tiers,omitempty"`
+}
+
+func DefaultBidIncrement() BidIncrement {
+	return BidIncrement{Type: IncrementFixed, Amount: 1}
+}
+
+func (b BidIncrement) Validate() error {
+	switch b.Type {
+	case IncrementFixed:
+		if b.Amount <= 0 {
+			return errors.New("bid increment amount must be greater than 0")
+		}
+	case IncrementPercent:
+		if b.Percent <= 0 || b.Percent > 100 {
+			return errors.New("bid increment percent must be between 0 and 100")
+		}
+	case IncrementTiered:
+		if len(b.Tiers) == 0 {
+			return errors.New("bid increment tiers are required")
+		}
+		for i, t := range b.Tiers {
+			if t.Amount <= 0 {
+				return errors.New("bid increment tier amount must be greater than 0")
+			}
+			if t.To != nil && *t.To <= t.From {
+				return errors.New("bid increment tier upper bound must be greater than its lower bound")
+			}
+			if i > 0 {
+				prev := b.Tiers[i-1]
+				if prev.To == nil || *prev.To != t.From {
+					return errors.New("bid increment tiers must be contiguous and ordered")
+				}
+			}
+		}
+		if b.Tiers[0].From != 0 {
+			return errors.New("bid increment tiers must start at 0")
+		}
+	default:
+		return fmt.Errorf("bid increment type is invalid, must be one of: %s, %s, %s", IncrementFixed, IncrementPercent, IncrementTiered)
+	}
+	return nil
+}
+
+// Step returns the smallest raise allowed over price.
+func (b BidIncrement) Step(price float64) float64 {
+	var step float64
+	switch b.Type {
+	case IncrementPercent:
+		step = pkg.RoundPrice(price * b.Percent / 100)
+	case IncrementTiered:
+		for _, t := range b.Tiers {
+			step = t.Amount
+			if price >= t.From && (t.To == nil || price < *t.To) {
+				break
+			}
+		}
+	default:
+		step = b.Amount
+	}
+	if step < 0.01 {
+		step = 0.01
+	}
+	return step
+}
+
+// MinimumBid returns the lowest acceptable next bid. The first bid may match
+// the starting price; every later one must clear the increment.
+func (b BidIncrement) MinimumBid(currentPrice float64, hasBids bool) float64 {
+	if !hasBids {
+		return currentPrice
+	}
+	return pkg.RoundPrice(currentPrice + b.Step(currentPrice))
+}
+
+// MinimumUnitBid is MinimumBid for an auction selling quantity units, where
+// currentPrice is the clearing price. While fewer units have been bid for
+// than are on offer, a bid at the starting price still wins some.
+func (b BidIncrement) MinimumUnitBid(startingPrice, currentPrice float64, hasBids bool, quantity, unitsDemanded int) float64 {
+	if quantity > 1 && unitsDemanded < quantity {
+		return startingPrice
+	}
+	return b.MinimumBid(currentPrice, hasBids)
+}
+
+func (b BidIncrement) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+func (b *BidIncrement) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, b)
+	case string:
+		return json.Unmarshal([]byte(v), b)
+	case nil:
+		*b = DefaultBidIncrement()
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into BidIncrement", src)
+	}
+}
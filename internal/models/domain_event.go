@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DomainEventDelivery is a domain event waiting to be published to one sink.
+// The event is written in the same transaction as the change it reports.
+type DomainEventDelivery struct {
+	EventID       uuid.UUID    `json:"event_id" db:"event_id"`
+	Seq           int64        `json:"seq" db:"seq"`
+	AuctionID     uuid.UUID    `json:"auction_id" db:"auction_id"`
+	AuctionSeq    int64        `json:"auction_seq" db:"auction_seq"`
+	Change        string       `json:"change" db:"change"`
+	BidIDs        []uuid.UUID  `json:"bid_ids" db:"bid_ids"`
+	Sink          string       `json:"sink" db:"sink"`
+	Status        OutboxStatus `json:"status" db:"status"`
+	Attempts      int          `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time    `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     *string      `json:"last_error,omitempty" db:"last_error"`
+	PublishedAt   *time.Time   `json:"published_at,omitempty" db:"published_at"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
+}
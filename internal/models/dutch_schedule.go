@@ -0,0 +1,58 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DutchSchedule is how a Dutch auction's price falls: every IntervalSeconds
+// the price drops by Decrement, never below FloorPrice.
+type DutchSchedule struct {
+	Decrement       float64 `json:"decrement"`
+	IntervalSeconds int     `json:"interval_seconds"`
+	FloorPrice      float64 `json:"floor_price"`
+}
+
+func (d DutchSchedule) Validate(startingPrice float64) error {
+	if d.Decrement <= 0 {
+		return errors.New("dutch decrement must be greater than 0")
+	}
+	if d.IntervalSeconds <= 0 {
+		return errors.New("dutch interval must be greater than 0 seconds")
+	}
+	if d.FloorPrice <= 0 {
+		return errors.New("dutch floor price must be greater than 0")
+	}
+	if d.FloorPrice >= startingPrice {
+		return errors.New("dutch floor price must be less than starting price")
+	}
+	return nil
+}
+
+// NextDrop returns when the price next falls after the drop at last, or nil
+// once the floor has been reached.
+func (d DutchSchedule) NextDrop(price float64, last time.Time) *time.Time {
+	if price <= d.FloorPrice {
+		return nil
+	}
+	next := last.Add(time.Duration(d.IntervalSeconds) * time.Second)
+	return &next
+}
+
+func (d DutchSchedule) Value() (driver.Value, error) {
+	return json.Marshal(d)
+}
+
+func (d *DutchSchedule) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	default:
+		return fmt.Errorf("cannot scan %T into DutchSchedule", src)
+	}
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LedgerAccountKind is what a ledger account holds. Every user has an
+// AVAILABLE and a HELD account; FUNDING is the single system account that
+// granted credit is drawn from, so its balance runs negative.
+type LedgerAccountKind string
+
+const (
+	AccountAvailable LedgerAccountKind = "AVAILABLE"
+	AccountHeld      LedgerAccountKind = "HELD"
+	AccountFunding   LedgerAccountKind = "FUNDING"
+)
+
+// LedgerTransactionKind is why money moved between two accounts.
+type LedgerTransactionKind string
+
+const (
+	// LedgerCredit grants a user credit from the funding account.
+	LedgerCredit LedgerTransactionKind = "CREDIT"
+	// LedgerHold commits available credit to a bid on an auction.
+	LedgerHold LedgerTransactionKind = "HOLD"
+	// LedgerRelease returns held credit once it is no longer needed.
+	LedgerRelease LedgerTransactionKind = "RELEASE"
+)
+
+type LedgerAccount struct {
+	ID        uuid.UUID         `json:"id" db:"id"`
+	UserID    *uuid.UUID        `json:"user_id,omitempty" db:"user_id"`
+	Kind      LedgerAccountKind `json:"kind" db:"kind"`
+	Balance   float64           `json:"balance" db:"balance"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// LedgerTransaction groups the entries of one movement of money. Its entries
+// always sum to zero.
+type LedgerTransaction struct {
+	ID        uuid.UUID             `json:"id" db:"id"`
+	Kind      LedgerTransactionKind `json:"kind" db:"kind"`
+	UserID    uuid.UUID             `json:"user_id" db:"user_id"`
+	AuctionID *uuid.UUID            `json:"auction_id,omitempty" db:"auction_id"`
+	CreatedBy *uuid.UUID            `json:"created_by,omitempty" db:"created_by"`
+	Memo      *string               `json:"memo,omitempty" db:"memo"`
+	CreatedAt time.Time             `json:"created_at" db:"created_at"`
+}
+
+type LedgerEntry struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	TransactionID uuid.UUID `json:"transaction_id" db:"transaction_id"`
+	AccountID     uuid.UUID `json:"account_id" db:"account_id"`
+	Amount        float64   `json:"amount" db:"amount"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreditHold is the credit a bidder currently has committed to an auction.
+type CreditHold struct {
+	AuctionID uuid.UUID `json:"auction_id" db:"auction_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Amount    float64   `json:"amount" db:"amount"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
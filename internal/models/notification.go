@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "PENDING"
+	OutboxDelivered OutboxStatus = "DELIVERED"
+	// OutboxFailed gave up after the last allowed attempt.
+	OutboxFailed OutboxStatus = "FAILED"
+)
+
+// OutboxNotification is a notification waiting to be delivered through one
+// channel. It is written in the same transaction as the change it reports.
+type OutboxNotification struct {
+	ID            uuid.UUID    `json:"id" db:"id"`
+	UserID        uuid.UUID    `json:"user_id" db:"user_id"`
+	AuctionID     *uuid.UUID   `json:"auction_id,omitempty" db:"auction_id"`
+	Channel       string       `json:"channel" db:"channel"`
+	Kind          string       `json:"kind" db:"kind"`
+	Subject       string       `json:"subject" db:"subject"`
+	Body          string       `json:"body" db:"body"`
+	Status        OutboxStatus `json:"status" db:"status"`
+	Attempts      int          `json:"attempts" db:"attempts"`
+	NextAttemptAt time.Time    `json:"next_attempt_at" db:"next_attempt_at"`
+	LastError     *string      `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt   *time.Time   `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt     time.Time    `json:"created_at" db:"created_at"`
+}
+
+// Notification is a message in a user's in-app inbox.
+type Notification struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	AuctionID *uuid.UUID `json:"auction_id,omitempty" db:"auction_id"`
+	Kind      string     `json:"kind" db:"kind"`
+	Subject   string     `json:"subject" db:"subject"`
+	Body      string     `json:"body" db:"body"`
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentStatus tracks collecting the money owed on a sold auction result.
+type PaymentStatus string
+
+const (
+	// PaymentPending is owed and not yet paid.
+	PaymentPending    PaymentStatus = "PENDING"
+	PaymentAuthorized PaymentStatus = "AUTHORIZED"
+	PaymentCaptured   PaymentStatus = "CAPTURED"
+	// PaymentFailed was declined; the winner may check out again.
+	PaymentFailed PaymentStatus = "FAILED"
+	// PaymentRefundPending has been sent to the provider for a refund.
+	PaymentRefundPending PaymentStatus = "REFUND_PENDING"
+	PaymentRefunded      PaymentStatus = "REFUNDED"
+)
+
+// paymentTransitions lists every legal payment status change.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentPending:       {PaymentAuthorized, PaymentFailed},
+	PaymentFailed:        {PaymentAuthorized, PaymentFailed},
+	PaymentAuthorized:    {PaymentCaptured, PaymentFailed},
+	PaymentCaptured:      {PaymentRefundPending, PaymentRefunded},
+	PaymentRefundPending: {PaymentRefunded, PaymentCaptured},
+}
+
+func (s PaymentStatus) CanMoveTo(to PaymentStatus) bool {
+	for _, next := range paymentTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentSource is what moved a payment to a new status.
+type PaymentSource string
+
+const (
+	PaymentSourceCheckout PaymentSource = "CHECKOUT"
+	PaymentSourceWebhook  PaymentSource = "WEBHOOK"
+	PaymentSourceAdmin    PaymentSource = "ADMIN"
+	// PaymentSourceSecondChance reset the payment for a runner-up who
+	// accepted a second-chance offer.
+	PaymentSourceSecondChance PaymentSource = "SECOND_CHANCE"
+)
+
+// PaymentStatusTransition is the audit row written for every payment status
+// change of an auction result.
+type PaymentStatusTransition struct {
+	ID          uuid.UUID     `json:"id" db:"id"`
+	ResultID    uuid.UUID     `json:"result_id" db:"result_id"`
+	FromStatus  PaymentStatus `json:"from_status" db:"from_status"`
+	ToStatus    PaymentStatus `json:"to_status" db:"to_status"`
+	Source      PaymentSource `json:"source" db:"source"`
+	ProviderRef *string       `json:"provider_ref,omitempty" db:"provider_ref"`
+	Amount      *float64      `json:"amount,omitempty" db:"amount"`
+	Detail      *string       `json:"detail,omitempty" db:"detail"`
+	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProxyBid is the hidden maximum a bidder is willing to pay for an auction.
+// The bid service counter-bids on the bidder's behalf up to MaxAmount.
+type ProxyBid struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	AuctionID uuid.UUID `json:"auction_id" db:"auction_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	MaxAmount float64   `json:"max_amount" db:"max_amount"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OfferStatus string
+
+const (
+	OfferPending  OfferStatus = "PENDING"
+	OfferAccepted OfferStatus = "ACCEPTED"
+	OfferDeclined OfferStatus = "DECLINED"
+	OfferExpired  OfferStatus = "EXPIRED"
+)
+
+// OfferSource is what prompted a second-chance offer: the seller, or the
+// worker once the winner's payment deadline passed.
+type OfferSource string
+
+const (
+	OfferSourceSeller  OfferSource = "SELLER"
+	OfferSourceTimeout OfferSource = "TIMEOUT"
+)
+
+// SecondChanceOffer offers an item whose winner did not pay to the next
+// highest bidder, at that bidder's last bid. Accepting it reassigns the
+// auction's settlement to them.
+type SecondChanceOffer struct {
+	ID               uuid.UUID   `json:"id" db:"id"`
+	AuctionID        uuid.UUID   `json:"auction_id" db:"auction_id"`
+	ResultID         uuid.UUID   `json:"result_id" db:"result_id"`
+	BidID            uuid.UUID   `json:"bid_id" db:"bid_id"`
+	UserID           uuid.UUID   `json:"user_id" db:"user_id"`
+	PreviousWinnerID *uuid.UUID  `json:"previous_winner_id,omitempty" db:"previous_winner_id"`
+	Amount           float64     `json:"amount" db:"amount"`
+	Status           OfferStatus `json:"status" db:"status"`
+	Source           OfferSource `json:"source" db:"source"`
+	CreatedBy        *uuid.UUID  `json:"created_by,omitempty" db:"created_by"`
+	ExpiresAt        time.Time   `json:"expires_at" db:"expires_at"`
+	RespondedAt      *time.Time  `json:"responded_at,omitempty" db:"responded_at"`
+	CreatedAt        time.Time   `json:"created_at" db:"created_at"`
+}
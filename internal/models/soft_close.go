@@ -0,0 +1,67 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SoftClose is an auction's anti-sniping policy: a bid placed in the last
+// WindowMinutes pushes end_time out by ExtensionMinutes, never further than
+// MaxExtensionMinutes past the originally scheduled end (0 means no limit).
+type SoftClose struct {
+	WindowMinutes       int `json:"window_minutes"`
+	ExtensionMinutes    int `json:"extension_minutes"`
+	MaxExtensionMinutes int `json:"max_extension_minutes,omitempty"`
+}
+
+func (s SoftClose) Validate() error {
+	if s.WindowMinutes <= 0 {
+		return errors.New("soft close window must be greater than 0 minutes")
+	}
+	if s.ExtensionMinutes <= 0 {
+		return errors.New("soft close extension must be greater than 0 minutes")
+	}
+	if s.MaxExtensionMinutes < 0 {
+		return errors.New("soft close maximum extension cannot be negative")
+	}
+	return nil
+}
+
+// Extend returns the end time after a bid placed at now and whether it moved.
+func (s SoftClose) Extend(now, endTime, originalEndTime time.Time) (time.Time, bool) {
+	window := time.Duration(s.WindowMinutes) * time.Minute
+	if now.Before(endTime.Add(-window)) {
+		return endTime, false
+	}
+
+	newEnd := endTime.Add(time.Duration(s.ExtensionMinutes) * time.Minute)
+	if s.MaxExtensionMinutes > 0 {
+		limit := originalEndTime.Add(time.Duration(s.MaxExtensionMinutes) * time.Minute)
+		if newEnd.After(limit) {
+			newEnd = limit
+		}
+	}
+
+	if !newEnd.After(endTime) {
+		return endTime, false
+	}
+	return newEnd, true
+}
+
+func (s SoftClose) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *SoftClose) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return fmt.Errorf("cannot scan %T into SoftClose", src)
+	}
+}
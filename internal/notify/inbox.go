@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// InboxStore persists in-app notifications for users to read later.
+type InboxStore interface {
+	AddToInbox(ctx context.Context, id, userID uuid.UUID, kind, subject, body string, auctionID *uuid.UUID) error
+}
+
+// InboxNotifier delivers messages to the user's in-app inbox. Once a message
+// is stored it is also handed to live, when set, to reach the user right away.
+type InboxNotifier struct {
+	store InboxStore
+	live  Notifier
+}
+
+func NewInboxNotifier(store InboxStore, live Notifier) *InboxNotifier {
+	return &InboxNotifier{store: store, live: live}
+}
+
+func (n *InboxNotifier) Notify(ctx context.Context, msg Message) error {
+	id := msg.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
+	if err := n.store.AddToInbox(ctx, id, msg.UserID, msg.Kind, msg.Subject, msg.Body, msg.AuctionID); err != nil {
+		return err
+	}
+	if n.live != nil {
+		msg.ID = id
+		if err := n.live.Notify(ctx, msg); err != nil {
+			log.Printf("notify: live delivery to %s: %v", msg.UserID, err)
+		}
+	}
+	return nil
+}
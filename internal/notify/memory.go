@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"context"
+	"sync"
+)
+
+// MemorySink keeps every message it is given, for tests and local tooling
+// that need to see what would have been sent. Fail makes the next n
+// deliveries return err, to exercise retries.
+type MemorySink struct {
+	mu       sync.Mutex
+	messages []Message
+	failures int
+	err      error
+}
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Notify(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return s.err
+	}
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func (s *MemorySink) Fail(n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures, s.err = n, err
+}
+
+// Messages returns a copy of the messages delivered so far.
+func (s *MemorySink) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message(nil), s.messages...)
+}
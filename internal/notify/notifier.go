@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// Channels a notification can be delivered through.
+const (
+	ChannelEmail   = "EMAIL"
+	ChannelWebhook = "WEBHOOK"
+	ChannelInApp   = "IN_APP"
+	ChannelLog     = "LOG"
+)
+
+// Kinds of notification sent to users.
+const (
+	KindOutbid               = "outbid"
+	KindSecondChanceOffer    = "second_chance_offer"
+	KindSecondChanceAccepted = "second_chance_accepted"
+	KindSecondChanceDeclined = "second_chance_declined"
+	KindAuctionEndingSoon    = "auction_ending_soon"
+	KindAuctionStarted       = "auction_started"
+	KindAuctionWon           = "auction_won"
+	KindPaymentCaptured      = "payment_captured"
+	KindPaymentFailed        = "payment_failed"
+	KindPaymentRefunded      = "payment_refunded"
+)
+
+// Message is a notification addressed to one user. ID, when set, stays the
+// same across redeliveries of the message so receivers can drop duplicates.
+type Message struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Kind      string
+	Subject   string
+	Body      string
+	AuctionID *uuid.UUID
+}
+
+// Notifier delivers messages to users.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// LogNotifier writes every message to the log, for development.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, msg Message) error {
+	log.Printf("notify %s [%s]: %s", msg.UserID, msg.Kind, msg.Subject)
+	return nil
+}
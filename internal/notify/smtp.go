@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// EmailLookup returns the address to email a user at.
+type EmailLookup func(ctx context.Context, userID uuid.UUID) (string, error)
+
+// SMTPNotifier emails messages through an SMTP relay. Any local stand-in
+// such as Mailpit works for development.
+type SMTPNotifier struct {
+	addr   string
+	auth   smtp.Auth
+	from   string
+	lookup EmailLookup
+}
+
+// NewSMTPNotifier returns an SMTP notifier for host:port. It authenticates
+// only when a username is set.
+func NewSMTPNotifier(host, port, username, password, from string, lookup EmailLookup) *SMTPNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPNotifier{
+		addr:   net.JoinHostPort(host, port),
+		auth:   auth,
+		from:   from,
+		lookup: lookup,
+	}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, msg Message) error {
+	to, err := n.lookup(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("look up email: %w", err)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", sanitizeHeader(msg.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	b.WriteString("\r\n")
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{to}, []byte(b.String())); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+	return nil
+}
+
+// sanitizeHeader keeps a header value on one line.
+func sanitizeHeader(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
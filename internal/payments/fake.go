@@ -0,0 +1,126 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Payment methods the fake gateway treats specially; any other non-empty
+// method is accepted.
+const (
+	FakeMethodDecline        = "tok_decline"
+	FakeMethodCaptureDecline = "tok_capture_decline"
+)
+
+type fakePayment struct {
+	method     string
+	authorized float64
+	captured   float64
+	refunded   float64
+}
+
+// FakeGateway is an in-memory provider for development and tests. It never
+// leaves the process: payments live in a map and webhooks are signed with
+// HMAC-SHA256 over the raw payload, hex encoded.
+type FakeGateway struct {
+	mu       sync.Mutex
+	secret   []byte
+	payments map[string]*fakePayment
+}
+
+func NewFakeGateway(webhookSecret string) *FakeGateway {
+	return &FakeGateway{
+		secret:   []byte(webhookSecret),
+		payments: make(map[string]*fakePayment),
+	}
+}
+
+func (g *FakeGateway) Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error) {
+	if req.PaymentMethod == "" {
+		return nil, fmt.Errorf("%w: payment method is required", ErrDeclined)
+	}
+	if req.PaymentMethod == FakeMethodDecline {
+		return nil, fmt.Errorf("%w: card declined", ErrDeclined)
+	}
+	if req.Amount <= 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than 0", ErrDeclined)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ref := "fake_pay_" + uuid.NewString()
+	g.payments[ref] = &fakePayment{method: req.PaymentMethod, authorized: req.Amount}
+	return &Authorization{ProviderRef: ref, Amount: req.Amount}, nil
+}
+
+func (g *FakeGateway) Capture(ctx context.Context, providerRef string, amount float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.payments[providerRef]
+	if !ok {
+		return ErrPaymentNotFound
+	}
+	if p.method == FakeMethodCaptureDecline {
+		return fmt.Errorf("%w: capture declined", ErrDeclined)
+	}
+	if p.captured > 0 {
+		return fmt.Errorf("%w: payment already captured", ErrDeclined)
+	}
+	if amount > p.authorized {
+		return fmt.Errorf("%w: capture exceeds authorized amount", ErrDeclined)
+	}
+	p.captured = amount
+	return nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, providerRef string, amount float64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	p, ok := g.payments[providerRef]
+	if !ok {
+		return ErrPaymentNotFound
+	}
+	if amount > p.captured-p.refunded {
+		return fmt.Errorf("%w: refund exceeds captured amount", ErrDeclined)
+	}
+	p.refunded += amount
+	return nil
+}
+
+func (g *FakeGateway) VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, g.sign(payload)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("decode webhook event: %w", err)
+	}
+	if event.ID == "" || event.ProviderRef == "" {
+		return nil, fmt.Errorf("decode webhook event: id and provider_ref are required")
+	}
+	return &event, nil
+}
+
+// SignWebhook returns the signature VerifyWebhook expects for payload, so
+// local tooling can post webhooks as the provider would.
+func (g *FakeGateway) SignWebhook(payload []byte) string {
+	return hex.EncodeToString(g.sign(payload))
+}
+
+func (g *FakeGateway) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
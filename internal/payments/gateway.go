@@ -0,0 +1,70 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrDeclined is returned when the provider refuses a payment method or
+	// an operation on a payment.
+	ErrDeclined = errors.New("payment declined")
+	// ErrInvalidSignature is returned for a webhook whose signature does not
+	// match its payload.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrPaymentNotFound is returned for a provider reference the provider
+	// does not know.
+	ErrPaymentNotFound = errors.New("payment not found")
+)
+
+// WebhookEventType is what a provider reports happened to a payment.
+type WebhookEventType string
+
+const (
+	EventPaymentCaptured WebhookEventType = "payment.captured"
+	EventPaymentFailed   WebhookEventType = "payment.failed"
+	EventPaymentRefunded WebhookEventType = "payment.refunded"
+)
+
+type AuthorizeRequest struct {
+	// Reference identifies what is being paid for, the auction result ID.
+	Reference     string
+	Amount        float64
+	Currency      string
+	PaymentMethod string
+}
+
+type Authorization struct {
+	ProviderRef string
+	Amount      float64
+}
+
+// WebhookEvent is a verified notification from the provider. ID is unique
+// per event so redeliveries can be recognised.
+type WebhookEvent struct {
+	ID          string           `json:"id"`
+	Type        WebhookEventType `json:"type"`
+	ProviderRef string           `json:"provider_ref"`
+	Amount      float64          `json:"amount"`
+}
+
+// Gateway is a payment provider. Money is first authorized against a
+// payment method, then captured; captured money can be refunded.
+type Gateway interface {
+	Authorize(ctx context.Context, req AuthorizeRequest) (*Authorization, error)
+	Capture(ctx context.Context, providerRef string, amount float64) error
+	Refund(ctx context.Context, providerRef string, amount float64) error
+	// VerifyWebhook checks a webhook's signature and decodes its event.
+	VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
+
+// NewGateway returns the gateway for a configured provider name.
+func NewGateway(provider, webhookSecret string) (Gateway, error) {
+	switch provider {
+	case "fake":
+		return NewFakeGateway(webhookSecret), nil
+	default:
+		return nil, fmt.Errorf("unknown payment provider %q", provider)
+	}
+}
@@ -6,12 +6,33 @@ import (
 	"errors"
 	"fmt"
 	"rebid/internal/dto"
+	"rebid/internal/models"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
+// reserveMetColumn exposes whether the reserve is met without ever selecting
+// the reserve itself.
+const reserveMetColumn = `(reserve_price IS NULL OR (current_bidder_id IS NOT NULL AND current_price >= reserve_price))`
+
+// setNextPriceDrop fills in when a running Dutch auction's price falls next.
+// The first drop is measured from start_time.
+func setNextPriceDrop(res *dto.ResponseAuction, priceDroppedAt *time.Time) {
+	if res.AuctionType != string(models.AuctionDutch) || res.DutchSchedule == nil {
+		return
+	}
+	if res.Status != string(models.AuctionActive) || res.CurrentBidderID != nil {
+		return
+	}
+	last := res.StartTime
+	if priceDroppedAt != nil {
+		last = *priceDroppedAt
+	}
+	res.NextPriceDropAt = res.DutchSchedule.NextDrop(res.CurrentPrice, last)
+}
+
 type AuctionRepository struct {
 	db        *sql.DB
 	imageRepo *ItemImageRepository
@@ -31,6 +52,21 @@ func (r *AuctionRepository) GetAll(ctx context.Context, filter *dto.FilterAuctio
 			a.end_time, 
 			a.current_bidder_id,
 			a.status, 
+			a.bid_increment,
+			a.soft_close,
+			` + reserveMetColumn + `,
+			a.outcome,
+			a.winner_id,
+			a.buy_now_price,
+			a.auction_type,
+			a.dutch_schedule,
+			a.price_dropped_at,
+			a.event_id,
+			a.lot_number,
+			a.quantity,
+			a.units_demanded,
+			a.deposit_required,
+			a.event_seq,
 			a.created_at as auction_created_at, 
 			a.updated_at as auction_updated_at,
 			i.id, i.user_id, i.name, i.description,
@@ -69,7 +105,17 @@ func (r *AuctionRepository) GetAll(ctx context.Context, filter *dto.FilterAuctio
 		argPos++
 	}
 
-	query += " ORDER BY a.created_at DESC"
+	if filter.EventID != nil {
+		query += fmt.Sprintf(" AND a.event_id = $%d", argPos)
+		args = append(args, *filter.EventID)
+		argPos++
+	}
+
+	if filter.EventID != nil {
+		query += " ORDER BY a.lot_number ASC"
+	} else {
+		query += " ORDER BY a.created_at DESC"
+	}
 
 	if filter.Limit > 0 {
 		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
@@ -93,6 +139,7 @@ func (r *AuctionRepository) GetAll(ctx context.Context, filter *dto.FilterAuctio
 			auctionUpdatedAt time.Time
 			itemCreatedAt    time.Time
 			itemUpdatedAt    sql.NullTime
+			priceDroppedAt   *time.Time
 		)
 
 		err := rows.Scan(
@@ -106,6 +153,21 @@ func (r *AuctionRepository) GetAll(ctx context.Context, filter *dto.FilterAuctio
 			&res.EndTime,
 			&res.CurrentBidderID,
 			&res.Status,
+			&res.BidIncrement,
+			&res.SoftClose,
+			&res.ReserveMet,
+			&res.Outcome,
+			&res.WinnerID,
+			&res.BuyNowPrice,
+			&res.AuctionType,
+			&res.DutchSchedule,
+			&priceDroppedAt,
+			&res.EventID,
+			&res.LotNumber,
+			&res.Quantity,
+			&res.UnitsDemanded,
+			&res.DepositRequired,
+			&res.Seq,
 			&auctionCreatedAt,
 			&auctionUpdatedAt,
 
@@ -124,6 +186,8 @@ func (r *AuctionRepository) GetAll(ctx context.Context, filter *dto.FilterAuctio
 			return nil, err
 		}
 
+		res.MinNextBid = res.BidIncrement.MinimumUnitBid(res.StartingPrice, res.CurrentPrice, res.CurrentBidderID != nil, res.Quantity, res.UnitsDemanded)
+		setNextPriceDrop(&res, priceDroppedAt)
 		res.CreatedAt = auctionCreatedAt.Format(time.RFC3339)
 		res.UpdatedAt = auctionUpdatedAt.Format(time.RFC3339)
 		item.CreatedAt = itemCreatedAt.Format(time.RFC3339)
@@ -156,18 +220,34 @@ func (r *AuctionRepository) GetAll(ctx context.Context, filter *dto.FilterAuctio
 
 func (r *AuctionRepository) Create(ctx context.Context, auction *dto.CreateAuctionRequest, userID uuid.UUID) (*dto.ResponseAuction, error) {
 	query := `
-    INSERT INTO auctions (id, item_id, description, created_by, starting_price, current_price, start_time, end_time, status, created_at, updated_at)
-    VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
-    RETURNING id, item_id, description, created_by, starting_price, current_price, start_time, end_time, current_bidder_id, status, created_at, updated_at
+    INSERT INTO auctions (id, item_id, description, created_by, starting_price, current_price, start_time, end_time, original_end_time, status, bid_increment, soft_close, reserve_price, buy_now_price, auction_type, dutch_schedule, event_id, lot_number, quantity, deposit_required, created_at, updated_at)
+    VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, NOW(), NOW())
+    RETURNING id, item_id, description, created_by, starting_price, current_price, start_time, end_time, current_bidder_id, status, bid_increment, soft_close, ` + reserveMetColumn + `, outcome, winner_id, buy_now_price, auction_type, dutch_schedule, price_dropped_at, event_id, lot_number, quantity, units_demanded, deposit_required, created_at, updated_at
 `
 
+	increment := models.DefaultBidIncrement()
+	if auction.BidIncrement != nil {
+		increment = *auction.BidIncrement
+	}
+
+	auctionType := models.AuctionEnglish
+	if auction.AuctionType != "" {
+		auctionType = models.AuctionType(auction.AuctionType)
+	}
+
+	quantity := 1
+	if auction.Quantity > 0 {
+		quantity = auction.Quantity
+	}
+
 	var response dto.ResponseAuction
 	var (
-		createdAt time.Time
-		updatedAt time.Time
+		createdAt      time.Time
+		updatedAt      time.Time
+		priceDroppedAt *time.Time
 	)
 
-	err := r.db.QueryRowContext(ctx, query, auction.ItemID, auction.Description, userID, auction.StartingPrice, auction.StartingPrice, auction.StartTime, auction.EndTime, auction.Status).Scan(
+	err := r.db.QueryRowContext(ctx, query, auction.ItemID, auction.Description, userID, auction.StartingPrice, auction.StartingPrice, auction.StartTime, auction.EndTime, auction.Status, increment, auction.SoftClose, auction.ReservePrice, auction.BuyNowPrice, auctionType, auction.DutchSchedule, auction.EventID, auction.LotNumber, quantity, auction.DepositRequired).Scan(
 		&response.ID,
 		&response.ItemID,
 		&response.Description,
@@ -178,6 +258,20 @@ func (r *AuctionRepository) Create(ctx context.Context, auction *dto.CreateAucti
 		&response.EndTime,
 		&response.CurrentBidderID,
 		&response.Status,
+		&response.BidIncrement,
+		&response.SoftClose,
+		&response.ReserveMet,
+		&response.Outcome,
+		&response.WinnerID,
+		&response.BuyNowPrice,
+		&response.AuctionType,
+		&response.DutchSchedule,
+		&priceDroppedAt,
+		&response.EventID,
+		&response.LotNumber,
+		&response.Quantity,
+		&response.UnitsDemanded,
+		&response.DepositRequired,
 		&createdAt,
 		&updatedAt,
 	)
@@ -188,34 +282,50 @@ func (r *AuctionRepository) Create(ctx context.Context, auction *dto.CreateAucti
 			if pqErr.Constraint == "unique_item_auction" {
 				return nil, fmt.Errorf("auction for this item already exists")
 			}
+			if pqErr.Constraint == "unique_event_lot" {
+				return nil, fmt.Errorf("lot number already taken")
+			}
 		}
 		return nil, fmt.Errorf("failed to create auction: %w", err)
 	}
 
+	response.MinNextBid = response.BidIncrement.MinimumUnitBid(response.StartingPrice, response.CurrentPrice, response.CurrentBidderID != nil, response.Quantity, response.UnitsDemanded)
+	setNextPriceDrop(&response, priceDroppedAt)
 	response.CreatedAt = createdAt.Format(time.RFC3339)
 	response.UpdatedAt = updatedAt.Format(time.RFC3339)
 	return &response, nil
 }
 
-func (r *AuctionRepository) Update(ctx context.Context, auction *dto.UpdateAuctionRequest, auctionID uuid.UUID) (*dto.ResponseAuction, error) {
+func (r *AuctionRepository) Update(ctx context.Context, tx *sql.Tx, auction *dto.UpdateAuctionRequest, auctionID uuid.UUID) (*dto.ResponseAuction, error) {
 	query := `
 		UPDATE auctions SET
 			starting_price = $1,
 			start_time     = $2,
-			end_time       = $3,
-			status         = $4,
-			updated_at     = NOW()
-		WHERE id = $5
-		RETURNING id, item_id, created_by, starting_price, current_price, start_time, end_time, current_bidder_id, status, created_at, updated_at
+			end_time          = $3,
+			status            = COALESCE($4, status),
+			bid_increment     = COALESCE($5, bid_increment),
+			soft_close        = $6,
+			reserve_price     = $7,
+			buy_now_price     = $8,
+			dutch_schedule    = COALESCE($9, dutch_schedule),
+			updated_at        = NOW()
+		WHERE id = $10
+		RETURNING id, item_id, created_by, starting_price, current_price, start_time, end_time, current_bidder_id, status, bid_increment, soft_close, ` + reserveMetColumn + `, outcome, winner_id, buy_now_price, auction_type, dutch_schedule, price_dropped_at, event_id, lot_number, quantity, units_demanded, deposit_required, event_seq, created_at, updated_at
 	`
 
 	var response dto.ResponseAuction
 	var (
-		createdAt time.Time
-		updatedAt time.Time
+		createdAt      time.Time
+		updatedAt      time.Time
+		priceDroppedAt *time.Time
 	)
 
-	err := r.db.QueryRowContext(ctx, query, auction.StartingPrice, auction.StartTime, auction.EndTime, auction.Status, auctionID).Scan(
+	var increment interface{}
+	if auction.BidIncrement != nil {
+		increment = *auction.BidIncrement
+	}
+
+	err := tx.QueryRowContext(ctx, query, auction.StartingPrice, auction.StartTime, auction.EndTime, auction.Status, increment, auction.SoftClose, auction.ReservePrice, auction.BuyNowPrice, auction.DutchSchedule, auctionID).Scan(
 		&response.ID,
 		&response.ItemID,
 		&response.CreatedBy,
@@ -225,6 +335,21 @@ func (r *AuctionRepository) Update(ctx context.Context, auction *dto.UpdateAucti
 		&response.EndTime,
 		&response.CurrentBidderID,
 		&response.Status,
+		&response.BidIncrement,
+		&response.SoftClose,
+		&response.ReserveMet,
+		&response.Outcome,
+		&response.WinnerID,
+		&response.BuyNowPrice,
+		&response.AuctionType,
+		&response.DutchSchedule,
+		&priceDroppedAt,
+		&response.EventID,
+		&response.LotNumber,
+		&response.Quantity,
+		&response.UnitsDemanded,
+		&response.DepositRequired,
+		&response.Seq,
 		&createdAt,
 		&updatedAt,
 	)
@@ -233,12 +358,42 @@ func (r *AuctionRepository) Update(ctx context.Context, auction *dto.UpdateAucti
 		return nil, fmt.Errorf("failed to update auction, %w", err)
 	}
 
+	response.MinNextBid = response.BidIncrement.MinimumUnitBid(response.StartingPrice, response.CurrentPrice, response.CurrentBidderID != nil, response.Quantity, response.UnitsDemanded)
+	setNextPriceDrop(&response, priceDroppedAt)
 	response.CreatedAt = createdAt.Format(time.RFC3339)
 	response.UpdatedAt = updatedAt.Format(time.RFC3339)
 	return &response, nil
 }
 
 func (r *AuctionRepository) GetByID(ctx context.Context, auctionID uuid.UUID) (*dto.ResponseAuction, error) {
+	return r.getByID(ctx, r.db, auctionID)
+}
+
+// GetWithBids reads an auction and every bid on it in one REPEATABLE READ
+// transaction, so the bids are exactly the ones behind the auction's seq.
+func (r *AuctionRepository) GetWithBids(ctx context.Context, auctionID uuid.UUID) (*dto.ResponseAuction, []dto.ResponseBidWithUser, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	auction, err := r.getByID(ctx, tx, auctionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	bids, err := listBidsByAuctionID(ctx, tx, auctionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return auction, bids, nil
+}
+
+func (r *AuctionRepository) getByID(ctx context.Context, q querier, auctionID uuid.UUID) (*dto.ResponseAuction, error) {
 	query := `
 		SELECT 
 			a.id, 
@@ -251,6 +406,21 @@ func (r *AuctionRepository) GetByID(ctx context.Context, auctionID uuid.UUID) (*
 			a.end_time, 
 			a.current_bidder_id, 
 			a.status, 
+			a.bid_increment,
+			a.soft_close,
+			` + reserveMetColumn + `,
+			a.outcome,
+			a.winner_id,
+			a.buy_now_price,
+			a.auction_type,
+			a.dutch_schedule,
+			a.price_dropped_at,
+			a.event_id,
+			a.lot_number,
+			a.quantity,
+			a.units_demanded,
+			a.deposit_required,
+			a.event_seq,
 			a.created_at, 
 			a.updated_at,
 			u.name as created_by_name,
@@ -269,13 +439,14 @@ func (r *AuctionRepository) GetByID(ctx context.Context, auctionID uuid.UUID) (*
 
 	var response dto.ResponseAuction
 	var (
-		createdAt time.Time
-		updatedAt time.Time
-		user      dto.UserDetailResponse
-		item      dto.ItemResponse
+		createdAt      time.Time
+		updatedAt      time.Time
+		priceDroppedAt *time.Time
+		user           dto.UserDetailResponse
+		item           dto.ItemResponse
 	)
 
-	err := r.db.QueryRowContext(ctx, query, auctionID).Scan(
+	err := q.QueryRowContext(ctx, query, auctionID).Scan(
 		&response.ID,
 		&response.ItemID,
 		&response.Description,
@@ -286,6 +457,21 @@ func (r *AuctionRepository) GetByID(ctx context.Context, auctionID uuid.UUID) (*
 		&response.EndTime,
 		&response.CurrentBidderID,
 		&response.Status,
+		&response.BidIncrement,
+		&response.SoftClose,
+		&response.ReserveMet,
+		&response.Outcome,
+		&response.WinnerID,
+		&response.BuyNowPrice,
+		&response.AuctionType,
+		&response.DutchSchedule,
+		&priceDroppedAt,
+		&response.EventID,
+		&response.LotNumber,
+		&response.Quantity,
+		&response.UnitsDemanded,
+		&response.DepositRequired,
+		&response.Seq,
 		&createdAt,
 		&updatedAt,
 		&user.Name,
@@ -305,6 +491,8 @@ func (r *AuctionRepository) GetByID(ctx context.Context, auctionID uuid.UUID) (*
 		return nil, fmt.Errorf("failed to get auction by ID, %w", err)
 	}
 
+	response.MinNextBid = response.BidIncrement.MinimumUnitBid(response.StartingPrice, response.CurrentPrice, response.CurrentBidderID != nil, response.Quantity, response.UnitsDemanded)
+	setNextPriceDrop(&response, priceDroppedAt)
 	response.CreatedAt = createdAt.Format(time.RFC3339)
 	response.UpdatedAt = updatedAt.Format(time.RFC3339)
 	response.User = user
@@ -368,7 +556,176 @@ func (r *AuctionRepository) UpdateCurrentPriceWithBidder(ctx context.Context, tx
 	return err
 }
 
-func (r *AuctionRepository) CloseExpiredAuctions(ctx context.Context) ([]uuid.UUID, error) {
+// StaggerEventLots keeps the lots of each event closing in sequence: a lot
+// may not end before the lot ahead of it plus the event's stagger, so when
+// soft close extends one lot every later lot is pushed back with it. Open
+// lots that had to move are returned. Ended lots still hold back the ones
+// after them; cancelled lots are ignored.
+func (r *AuctionRepository) StaggerEventLots(ctx context.Context, tx *sql.Tx) ([]uuid.UUID, error) {
+	query := `
+		WITH required AS (
+			SELECT a.id,
+				MAX(a.end_time - make_interval(secs => a.lot_number * e.close_stagger_seconds))
+					OVER (PARTITION BY a.event_id ORDER BY a.lot_number)
+					+ make_interval(secs => a.lot_number * e.close_stagger_seconds) AS min_end_time
+			FROM auctions a
+			JOIN auction_events e ON e.id = a.event_id
+			WHERE a.status IN ('SCHEDULED', 'ACTIVE', 'ENDED')
+		)
+		UPDATE auctions a SET end_time = r.min_end_time, updated_at = NOW()
+		FROM required r
+		WHERE a.id = r.id
+			AND a.status IN ('SCHEDULED', 'ACTIVE')
+			AND a.end_time < r.min_end_time
+		RETURNING a.id
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("stagger event lots: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("stagger event lots scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ResolveSealedBids opens the bids of a sealed-bid auction and writes the
+// winner and clearing price, leaving other auction types untouched. The
+// highest bid wins, the earliest one on a tie. First-price winners pay their
+// bid; second-price winners pay the runner-up's bid, raised to the starting
+// price and to the reserve when their own bid meets it.
+func (r *AuctionRepository) ResolveSealedBids(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) error {
+	q := `
+		WITH ranked AS (
+			SELECT user_id, amount, ROW_NUMBER() OVER (ORDER BY amount DESC, bid_time ASC) AS rn
+			FROM bids
+			WHERE auction_id = $1 AND status = 'VALID'
+		),
+		top AS (
+			SELECT w.user_id, w.amount AS top_amount, (SELECT amount FROM ranked WHERE rn = 2) AS second_amount
+			FROM ranked w
+			WHERE w.rn = 1
+		)
+		UPDATE auctions a SET
+			current_bidder_id = top.user_id,
+			current_price     = CASE
+				WHEN a.auction_type = 'SEALED_FIRST_PRICE' THEN top.top_amount
+				ELSE LEAST(top.top_amount, GREATEST(
+					top.second_amount,
+					a.starting_price,
+					CASE WHEN a.reserve_price <= top.top_amount THEN a.reserve_price END
+				))
+			END,
+			updated_at = NOW()
+		FROM top
+		WHERE a.id = $1 AND a.auction_type IN ('SEALED_FIRST_PRICE', 'SEALED_SECOND_PRICE')
+	`
+	if _, err := tx.ExecContext(ctx, q, auctionID); err != nil {
+		return fmt.Errorf("resolve sealed bids: %w", err)
+	}
+	return nil
+}
+
+// RepriceUnits recomputes a multi-quantity auction from its standing bids:
+// the price becomes the uniform clearing price, the lowest bid still winning
+// units, and the leader the highest bidder. With no valid bids left it falls
+// back to the starting price with no leader.
+func (r *AuctionRepository) RepriceUnits(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (float64, *uuid.UUID, error) {
+	q := `
+		WITH ` + bidAllocationCTE + `,
+		summary AS (
+			SELECT MIN(amount) FILTER (WHERE units_won > 0) AS clearing_price,
+				(ARRAY_AGG(user_id ORDER BY amount DESC, bid_time ASC))[1] AS leader_id,
+				COALESCE(SUM(quantity), 0) AS units_demanded
+			FROM allocation
+		)
+		UPDATE auctions a SET
+			current_price     = COALESCE(s.clearing_price, a.starting_price),
+			current_bidder_id = s.leader_id,
+			units_demanded    = s.units_demanded,
+			updated_at        = NOW()
+		FROM summary s
+		WHERE a.id = $1
+		RETURNING a.current_price, a.current_bidder_id
+	`
+	var price float64
+	var leader *uuid.UUID
+	if err := tx.QueryRowContext(ctx, q, auctionID).Scan(&price, &leader); err != nil {
+		return 0, nil, fmt.Errorf("reprice auction units: %w", err)
+	}
+	return price, leader, nil
+}
+
+// ResetCurrentPrice puts an auction without valid bids back to its starting
+// price with no leader.
+func (r *AuctionRepository) ResetCurrentPrice(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (float64, error) {
+	q := `UPDATE auctions SET current_price = starting_price, current_bidder_id = NULL, updated_at = NOW() WHERE id = $1 RETURNING current_price`
+	var price float64
+	err := tx.QueryRowContext(ctx, q, auctionID).Scan(&price)
+	return price, err
+}
+
+// ClearBuyNowPrice withdraws the buy-now offer once bidding has made it
+// unattractive to the seller.
+func (r *AuctionRepository) ClearBuyNowPrice(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) error {
+	q := `UPDATE auctions SET buy_now_price = NULL, updated_at = NOW() WHERE id = $1`
+	_, err := tx.ExecContext(ctx, q, auctionID)
+	return err
+}
+
+func (r *AuctionRepository) EndAuction(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) error {
+	q := `UPDATE auctions SET status = 'ENDED', end_time = LEAST(end_time, NOW()), updated_at = NOW() WHERE id = $1`
+	_, err := tx.ExecContext(ctx, q, auctionID)
+	return err
+}
+
+func (r *AuctionRepository) ExtendEndTime(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, endTime time.Time) error {
+	q := `UPDATE auctions SET end_time = $1, updated_at = NOW() WHERE id = $2`
+	_, err := tx.ExecContext(ctx, q, endTime, auctionID)
+	return err
+}
+
+// DecideOutcome records how an ENDED auction finished. The current bidder only
+// wins when the reserve, if any, was met. Multi-quantity auctions have no
+// single winner; their winners are the settlements written at close.
+func (r *AuctionRepository) DecideOutcome(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) error {
+	q := `
+		UPDATE auctions
+		SET outcome = CASE
+				WHEN current_bidder_id IS NULL THEN 'NO_BIDS'
+				WHEN ` + reserveMetColumn + ` THEN 'SOLD'
+				ELSE 'RESERVE_NOT_MET'
+			END,
+			winner_id = CASE WHEN quantity = 1 AND current_bidder_id IS NOT NULL AND ` + reserveMetColumn + ` THEN current_bidder_id END
+		WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, q, auctionID); err != nil {
+		return fmt.Errorf("decide auction outcome: %w", err)
+	}
+	return nil
+}
+
+// ReassignWinner hands a sold, ended auction to a new winner at price, after
+// its first winner failed to pay.
+func (r *AuctionRepository) ReassignWinner(ctx context.Context, tx *sql.Tx, auctionID, winnerID uuid.UUID, price float64) error {
+	q := `UPDATE auctions SET winner_id = $1, current_bidder_id = $1, current_price = $2, updated_at = NOW() WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, q, winnerID, price, auctionID); err != nil {
+		return fmt.Errorf("reassign auction winner: %w", err)
+	}
+	return nil
+}
+
+// CloseExpiredAuctions ends every ACTIVE auction past its end_time. A bid that
+// extends end_time holds the row lock, so this UPDATE waits for it and then
+// re-checks end_time against the committed value.
+func (r *AuctionRepository) CloseExpiredAuctions(ctx context.Context, tx *sql.Tx) ([]uuid.UUID, error) {
 	const q = `
 		UPDATE auctions
 		SET status = 'ENDED', updated_at = NOW()
@@ -376,7 +733,7 @@ func (r *AuctionRepository) CloseExpiredAuctions(ctx context.Context) ([]uuid.UU
 		RETURNING id
 	`
 
-	rows, err := r.db.QueryContext(ctx, q)
+	rows, err := tx.QueryContext(ctx, q)
 	if err != nil {
 		return nil, fmt.Errorf("close expired auctions: %w", err)
 	}
@@ -393,17 +750,115 @@ func (r *AuctionRepository) CloseExpiredAuctions(ctx context.Context) ([]uuid.UU
 	return ids, rows.Err()
 }
 
+func (r *AuctionRepository) ActivateScheduledAuctions(ctx context.Context, tx *sql.Tx) ([]uuid.UUID, error) {
+	const q = `
+		UPDATE auctions
+		SET status = 'ACTIVE', updated_at = NOW()
+		WHERE status = 'SCHEDULED' AND start_time <= NOW()
+		RETURNING id
+	`
+
+	rows, err := tx.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("activate scheduled auctions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("activate scheduled auctions scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DropDutchPrices lowers the price of every running Dutch auction that is due
+// for one or more drops, catching up on any ticks that were missed, and
+// returns their IDs. Rows are locked so a drop never races a bid accepting
+// the previous price.
+func (r *AuctionRepository) DropDutchPrices(ctx context.Context, tx *sql.Tx) ([]uuid.UUID, error) {
+	query := `
+		WITH due AS (
+			SELECT id,
+				FLOOR(EXTRACT(EPOCH FROM (NOW() - COALESCE(price_dropped_at, start_time))) / (dutch_schedule->>'interval_seconds')::int) AS steps
+			FROM auctions
+			WHERE auction_type = 'DUTCH'
+				AND status = 'ACTIVE'
+				AND current_bidder_id IS NULL
+				AND end_time > NOW()
+				AND current_price > (dutch_schedule->>'floor_price')::numeric
+			FOR UPDATE
+		)
+		UPDATE auctions a SET
+			current_price    = GREATEST((a.dutch_schedule->>'floor_price')::numeric, a.current_price - (a.dutch_schedule->>'decrement')::numeric * due.steps),
+			price_dropped_at = COALESCE(a.price_dropped_at, a.start_time) + make_interval(secs => due.steps * (a.dutch_schedule->>'interval_seconds')::int),
+			updated_at       = NOW()
+		FROM due
+		WHERE a.id = due.id AND due.steps >= 1
+		RETURNING a.id
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("drop dutch prices: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("drop dutch prices scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 type AuctionBidEligibility struct {
-	CurrentPrice float64
-	Status       string
-	EndTime      time.Time
+	CurrentPrice    float64
+	CurrentBidderID *uuid.UUID
+	Status          string
+	EndTime         time.Time
+	BidIncrement    models.BidIncrement
+	SoftClose       *models.SoftClose
+	OriginalEndTime time.Time
+	BuyNowPrice     *float64
+	CreatedBy       uuid.UUID
+	AuctionType     models.AuctionType
+	StartingPrice   float64
+	Quantity        int
+	UnitsDemanded   int
+	DepositRequired bool
+}
+
+func (e *AuctionBidEligibility) MinimumBid() float64 {
+	return e.BidIncrement.MinimumUnitBid(e.StartingPrice, e.CurrentPrice, e.CurrentBidderID != nil, e.Quantity, e.UnitsDemanded)
+}
+
+// LockAuctionForBid reads the bid-relevant columns and holds the row lock
+// until tx ends, serializing concurrent bids on the same auction.
+func (r *AuctionRepository) LockAuctionForBid(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (*AuctionBidEligibility, error) {
+	const q = `SELECT current_price, current_bidder_id, status, end_time, bid_increment, soft_close, original_end_time, buy_now_price, created_by, auction_type, starting_price, quantity, units_demanded, deposit_required FROM auctions WHERE id = $1 FOR UPDATE`
+
+	var e AuctionBidEligibility
+	err := tx.QueryRowContext(ctx, q, auctionID).Scan(&e.CurrentPrice, &e.CurrentBidderID, &e.Status, &e.EndTime, &e.BidIncrement, &e.SoftClose, &e.OriginalEndTime, &e.BuyNowPrice, &e.CreatedBy, &e.AuctionType, &e.StartingPrice, &e.Quantity, &e.UnitsDemanded, &e.DepositRequired)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("auction not found")
+		}
+		return nil, fmt.Errorf("lock auction for bid: %w", err)
+	}
+	return &e, nil
 }
 
 func (r *AuctionRepository) GetAuctionForBid(ctx context.Context, auctionID uuid.UUID) (*AuctionBidEligibility, error) {
-	const q = `SELECT current_price, status, end_time FROM auctions WHERE id = $1`
+	const q = `SELECT current_price, current_bidder_id, status, end_time, bid_increment, soft_close, original_end_time, buy_now_price, created_by, auction_type, starting_price, quantity, units_demanded, deposit_required FROM auctions WHERE id = $1`
 
 	var e AuctionBidEligibility
-	err := r.db.QueryRowContext(ctx, q, auctionID).Scan(&e.CurrentPrice, &e.Status, &e.EndTime)
+	err := r.db.QueryRowContext(ctx, q, auctionID).Scan(&e.CurrentPrice, &e.CurrentBidderID, &e.Status, &e.EndTime, &e.BidIncrement, &e.SoftClose, &e.OriginalEndTime, &e.BuyNowPrice, &e.CreatedBy, &e.AuctionType, &e.StartingPrice, &e.Quantity, &e.UnitsDemanded, &e.DepositRequired)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("auction not found")
@@ -412,3 +867,65 @@ func (r *AuctionRepository) GetAuctionForBid(ctx context.Context, auctionID uuid
 	}
 	return &e, nil
 }
+
+type AuctionTransitionState struct {
+	Status      models.AuctionStatus
+	CreatedBy   uuid.UUID
+	HasBids     bool
+	AuctionType models.AuctionType
+	Quantity    int
+	// The terms an edit may change while nobody has bid.
+	StartingPrice float64
+	StartTime     time.Time
+	EndTime       time.Time
+	BidIncrement  models.BidIncrement
+	SoftClose     *models.SoftClose
+	ReservePrice  *float64
+	BuyNowPrice   *float64
+	DutchSchedule *models.DutchSchedule
+}
+
+func (r *AuctionRepository) LockForTransition(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (*AuctionTransitionState, error) {
+	const q = `
+		SELECT status, created_by, EXISTS (SELECT 1 FROM bids WHERE bids.auction_id = auctions.id AND bids.status = 'VALID'), auction_type, quantity,
+			starting_price, start_time, end_time, bid_increment, soft_close, reserve_price, buy_now_price, dutch_schedule
+		FROM auctions
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	var st AuctionTransitionState
+	err := tx.QueryRowContext(ctx, q, auctionID).Scan(
+		&st.Status,
+		&st.CreatedBy,
+		&st.HasBids,
+		&st.AuctionType,
+		&st.Quantity,
+		&st.StartingPrice,
+		&st.StartTime,
+		&st.EndTime,
+		&st.BidIncrement,
+		&st.SoftClose,
+		&st.ReservePrice,
+		&st.BuyNowPrice,
+		&st.DutchSchedule,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("auction not found")
+		}
+		return nil, fmt.Errorf("lock auction for transition: %w", err)
+	}
+	return &st, nil
+}
+
+func (r *AuctionRepository) RecordStatusTransition(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, from, to models.AuctionStatus, actor models.AuctionActor, actorID *uuid.UUID) error {
+	const q = `
+		INSERT INTO auction_status_transitions (id, auction_id, from_status, to_status, actor_type, actor_id, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, NOW())
+	`
+	if _, err := tx.ExecContext(ctx, q, auctionID, from, to, actor, actorID); err != nil {
+		return fmt.Errorf("record status transition: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// eventStatusColumn derives an event's status from its schedule and lots:
+// SCHEDULED before it starts, ACTIVE while any lot is still open, ENDED after.
+const eventStatusColumn = `(CASE
+	WHEN NOW() < e.start_time THEN 'SCHEDULED'
+	WHEN EXISTS (SELECT 1 FROM auctions l WHERE l.event_id = e.id AND l.status IN ('SCHEDULED', 'ACTIVE')) THEN 'ACTIVE'
+	ELSE 'ENDED'
+END)`
+
+type AuctionEventRepository struct {
+	db *sql.DB
+}
+
+func NewAuctionEventRepository(db *sql.DB) *AuctionEventRepository {
+	return &AuctionEventRepository{
+		db: db,
+	}
+}
+
+func (r *AuctionEventRepository) Create(ctx context.Context, event *dto.CreateAuctionEventRequest, userID uuid.UUID) (*models.AuctionEvent, error) {
+	query := `
+		INSERT INTO auction_events (id, name, description, created_by, start_time, first_lot_end_time, close_stagger_seconds, created_at, updated_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, NOW(), NOW())
+		RETURNING id, name, description, created_by, start_time, first_lot_end_time, close_stagger_seconds, created_at, updated_at
+	`
+	var e models.AuctionEvent
+	err := r.db.QueryRowContext(ctx, query, event.Name, event.Description, userID, event.StartTime, event.FirstLotEndTime, event.CloseStaggerSeconds).Scan(
+		&e.ID,
+		&e.Name,
+		&e.Description,
+		&e.CreatedBy,
+		&e.StartTime,
+		&e.FirstLotEndTime,
+		&e.CloseStaggerSeconds,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create auction event: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *AuctionEventRepository) GetByID(ctx context.Context, eventID uuid.UUID) (*models.AuctionEvent, error) {
+	query := `
+		SELECT id, name, description, created_by, start_time, first_lot_end_time, close_stagger_seconds, created_at, updated_at
+		FROM auction_events
+		WHERE id = $1
+	`
+	var e models.AuctionEvent
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(
+		&e.ID,
+		&e.Name,
+		&e.Description,
+		&e.CreatedBy,
+		&e.StartTime,
+		&e.FirstLotEndTime,
+		&e.CloseStaggerSeconds,
+		&e.CreatedAt,
+		&e.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("auction event not found")
+		}
+		return nil, fmt.Errorf("failed to get auction event: %w", err)
+	}
+	return &e, nil
+}
+
+// GetAll lists events with their derived status and lot count. The time
+// filters bound the event's start.
+func (r *AuctionEventRepository) GetAll(ctx context.Context, filter *dto.FilterAuctionEvent) ([]dto.ResponseAuctionEvent, error) {
+	query := `
+		SELECT * FROM (
+			SELECT
+				e.id, e.name, e.description, e.created_by,
+				e.start_time, e.first_lot_end_time, e.close_stagger_seconds,
+				` + eventStatusColumn + ` AS status,
+				(SELECT COUNT(*) FROM auctions l WHERE l.event_id = e.id) AS lot_count,
+				e.created_at, e.updated_at,
+				u.name AS user_name, u.email AS user_email
+			FROM auction_events e
+			LEFT JOIN users u ON e.created_by = u.id
+		) ev
+		WHERE 1=1
+	`
+
+	var args []interface{}
+	argPos := 1
+
+	if filter.Status != nil {
+		query += fmt.Sprintf(" AND ev.status = $%d", argPos)
+		args = append(args, *filter.Status)
+		argPos++
+	}
+
+	if filter.CreatedBy != nil {
+		query += fmt.Sprintf(" AND ev.created_by = $%d", argPos)
+		args = append(args, *filter.CreatedBy)
+		argPos++
+	}
+
+	if filter.StartTime != nil {
+		query += fmt.Sprintf(" AND ev.start_time >= $%d", argPos)
+		args = append(args, *filter.StartTime)
+		argPos++
+	}
+
+	if filter.EndTime != nil {
+		query += fmt.Sprintf(" AND ev.start_time <= $%d", argPos)
+		args = append(args, *filter.EndTime)
+		argPos++
+	}
+
+	query += " ORDER BY ev.start_time ASC"
+
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auction events: %w", err)
+	}
+	defer rows.Close()
+
+	responses := []dto.ResponseAuctionEvent{}
+	for rows.Next() {
+		var res dto.ResponseAuctionEvent
+		var createdAt, updatedAt time.Time
+		var userName, userEmail sql.NullString
+		if err := rows.Scan(
+			&res.ID,
+			&res.Name,
+			&res.Description,
+			&res.CreatedBy,
+			&res.StartTime,
+			&res.FirstLotEndTime,
+			&res.CloseStaggerSeconds,
+			&res.Status,
+			&res.LotCount,
+			&createdAt,
+			&updatedAt,
+			&userName,
+			&userEmail,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan auction event row: %w", err)
+		}
+		res.User = &dto.UserDetailResponse{Name: userName.String, Email: userEmail.String}
+		res.CreatedAt = createdAt.Format(time.RFC3339)
+		res.UpdatedAt = updatedAt.Format(time.RFC3339)
+		responses = append(responses, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return responses, nil
+}
+
+// GetStatus returns the derived status of one event.
+func (r *AuctionEventRepository) GetStatus(ctx context.Context, eventID uuid.UUID) (string, error) {
+	query := `SELECT ` + eventStatusColumn + ` FROM auction_events e WHERE e.id = $1`
+	var status string
+	if err := r.db.QueryRowContext(ctx, query, eventID).Scan(&status); err != nil {
+		return "", fmt.Errorf("failed to get auction event status: %w", err)
+	}
+	return status, nil
+}
+
+// NextLotNumber returns the number the next lot added to the event gets.
+func (r *AuctionEventRepository) NextLotNumber(ctx context.Context, eventID uuid.UUID) (int, error) {
+	query := `SELECT COALESCE(MAX(lot_number), 0) + 1 FROM auctions WHERE event_id = $1`
+	var lot int
+	if err := r.db.QueryRowContext(ctx, query, eventID).Scan(&lot); err != nil {
+		return 0, fmt.Errorf("failed to get next lot number: %w", err)
+	}
+	return lot, nil
+}
@@ -0,0 +1,216 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/pkg"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const auctionResultColumns = `r.id, r.auction_id, a.description, r.seller_id, r.winner_id, r.outcome, r.hammer_price, r.quantity, r.buyer_fee, r.seller_fee, r.payment_status, r.closed_at`
+
+type AuctionResultRepository struct {
+	db *sql.DB
+}
+
+func NewAuctionResultRepository(db *sql.DB) *AuctionResultRepository {
+	return &AuctionResultRepository{
+		db: db,
+	}
+}
+
+// Create settles an ended auction from its decided outcome. Fees are
+// percentages of the hammer price and only apply to sold auctions. A sold
+// multi-quantity auction gets one settlement per winner, for the units they
+// won at the uniform clearing price. Sold results start out awaiting payment.
+// An auction is settled once; later calls are no-ops.
+func (r *AuctionResultRepository) Create(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, buyerFeePercent, sellerFeePercent float64) error {
+	query := `
+		INSERT INTO auction_results (id, auction_id, seller_id, winner_id, outcome, hammer_price, quantity, buyer_fee, seller_fee, payment_status, closed_at, created_at)
+		SELECT gen_random_uuid(), id, created_by, winner_id, outcome,
+			CASE WHEN outcome = 'SOLD' THEN current_price END,
+			CASE WHEN outcome = 'SOLD' THEN 1 ELSE 0 END,
+			CASE WHEN outcome = 'SOLD' THEN ROUND(current_price * $2 / 100, 2) ELSE 0 END,
+			CASE WHEN outcome = 'SOLD' THEN ROUND(current_price * $3 / 100, 2) ELSE 0 END,
+			CASE WHEN outcome = 'SOLD' THEN 'PENDING' END,
+			NOW(), NOW()
+		FROM auctions
+		WHERE id = $1 AND outcome IS NOT NULL
+			AND (quantity = 1 OR outcome <> 'SOLD')
+			AND NOT EXISTS (SELECT 1 FROM auction_results WHERE auction_id = $1)
+	`
+	if _, err := tx.ExecContext(ctx, query, auctionID, buyerFeePercent, sellerFeePercent); err != nil {
+		return fmt.Errorf("failed to create auction result: %w", err)
+	}
+
+	unitsQuery := `
+		WITH ` + bidAllocationCTE + `
+		INSERT INTO auction_results (id, auction_id, seller_id, winner_id, outcome, hammer_price, quantity, buyer_fee, seller_fee, payment_status, closed_at, created_at)
+		SELECT gen_random_uuid(), a.id, a.created_by, al.user_id, a.outcome, a.current_price, al.units_won,
+			ROUND(a.current_price * al.units_won * $2 / 100, 2),
+			ROUND(a.current_price * al.units_won * $3 / 100, 2),
+			'PENDING', NOW(), NOW()
+		FROM auctions a
+		JOIN allocation al ON al.units_won > 0
+		WHERE a.id = $1 AND a.quantity > 1 AND a.outcome = 'SOLD'
+			AND NOT EXISTS (SELECT 1 FROM auction_results WHERE auction_id = $1)
+	`
+	if _, err := tx.ExecContext(ctx, unitsQuery, auctionID, buyerFeePercent, sellerFeePercent); err != nil {
+		return fmt.Errorf("failed to create auction unit results: %w", err)
+	}
+	return nil
+}
+
+// Reassign moves a single-unit settlement to a new winner at price. Fees are
+// recomputed and the payment starts over from PENDING, which is written to
+// the payment history as a change from the lapsed winner's status.
+func (r *AuctionResultRepository) Reassign(ctx context.Context, tx *sql.Tx, resultID uuid.UUID, from models.PaymentStatus, winnerID uuid.UUID, price, buyerFeePercent, sellerFeePercent float64) error {
+	query := `
+		UPDATE auction_results
+		SET winner_id = $2, hammer_price = $3,
+			buyer_fee = ROUND($3 * $4 / 100, 2),
+			seller_fee = ROUND($3 * $5 / 100, 2),
+			payment_status = 'PENDING', payment_provider_ref = NULL
+		WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, resultID, winnerID, price, buyerFeePercent, sellerFeePercent); err != nil {
+		return fmt.Errorf("failed to reassign auction result: %w", err)
+	}
+
+	query = `
+		INSERT INTO payment_status_transitions (id, result_id, from_status, to_status, source, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, NOW())
+	`
+	if _, err := tx.ExecContext(ctx, query, resultID, from, models.PaymentPending, models.PaymentSourceSecondChance); err != nil {
+		return fmt.Errorf("failed to record payment status transition: %w", err)
+	}
+	return nil
+}
+
+// ListByAuctionID returns the settlements of an auction: one for a
+// single-unit auction, one per winner for a sold multi-quantity auction.
+func (r *AuctionResultRepository) ListByAuctionID(ctx context.Context, auctionID uuid.UUID) ([]dto.ResponseAuctionResult, error) {
+	results, err := r.list(ctx, `r.auction_id = $1`, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("auction result not found")
+	}
+	return results, nil
+}
+
+// ListWinners returns the settlements of a sold auction inside tx, so they
+// can be read right after Create made them.
+func (r *AuctionResultRepository) ListWinners(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) ([]dto.ResponseAuctionResult, error) {
+	query := `
+		SELECT ` + auctionResultColumns + `
+		FROM auction_results r
+		JOIN auctions a ON r.auction_id = a.id
+		WHERE r.auction_id = $1 AND r.outcome = 'SOLD' AND r.winner_id IS NOT NULL
+		ORDER BY r.quantity DESC
+	`
+	rows, err := tx.QueryContext(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auction winners: %w", err)
+	}
+	defer rows.Close()
+
+	response := []dto.ResponseAuctionResult{}
+	for rows.Next() {
+		result, err := scanAuctionResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan auction result row: %w", err)
+		}
+		response = append(response, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return response, nil
+}
+
+// ListWonByUser returns the auctions userID won, most recent first.
+func (r *AuctionResultRepository) ListWonByUser(ctx context.Context, userID uuid.UUID) ([]dto.ResponseAuctionResult, error) {
+	return r.list(ctx, `r.winner_id = $1`, userID)
+}
+
+// ListSoldByUser returns the auctions userID sold, most recent first.
+// Auctions that ended without a sale are not included.
+func (r *AuctionResultRepository) ListSoldByUser(ctx context.Context, userID uuid.UUID) ([]dto.ResponseAuctionResult, error) {
+	return r.list(ctx, `r.seller_id = $1 AND r.outcome = 'SOLD'`, userID)
+}
+
+func (r *AuctionResultRepository) list(ctx context.Context, where string, id uuid.UUID) ([]dto.ResponseAuctionResult, error) {
+	query := `
+		SELECT ` + auctionResultColumns + `
+		FROM auction_results r
+		JOIN auctions a ON r.auction_id = a.id
+		WHERE ` + where + `
+		ORDER BY r.closed_at DESC, r.quantity DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list auction results: %w", err)
+	}
+	defer rows.Close()
+
+	response := []dto.ResponseAuctionResult{}
+	for rows.Next() {
+		result, err := scanAuctionResult(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan auction result row: %w", err)
+		}
+		response = append(response, *result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return response, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// querier is what *sql.DB and *sql.Tx have in common, for reads that run
+// on their own or inside a transaction.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func scanAuctionResult(row rowScanner) (*dto.ResponseAuctionResult, error) {
+	var result dto.ResponseAuctionResult
+	var closedAt time.Time
+	err := row.Scan(
+		&result.ID,
+		&result.AuctionID,
+		&result.Description,
+		&result.SellerID,
+		&result.WinnerID,
+		&result.Outcome,
+		&result.HammerPrice,
+		&result.Quantity,
+		&result.BuyerFee,
+		&result.SellerFee,
+		&result.PaymentStatus,
+		&closedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.HammerPrice != nil {
+		gross := *result.HammerPrice * float64(result.Quantity)
+		result.BuyerTotal = pkg.RoundPrice(gross + result.BuyerFee)
+		result.SellerPayout = pkg.RoundPrice(gross - result.SellerFee)
+	}
+	result.ClosedAt = closedAt.Format(time.RFC3339)
+	return &result, nil
+}
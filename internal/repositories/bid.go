@@ -3,13 +3,36 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"rebid/internal/dto"
+	"rebid/internal/models"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// bidAllocationCTE defines, for the auction in $1, each bidder's standing bid
+// (their latest valid one) and how many of the auction's units it wins when
+// the highest bids are filled first, the earliest one on a tie. The lowest
+// winning bid may be filled only in part.
+const bidAllocationCTE = `
+	standing AS (
+		SELECT DISTINCT ON (user_id) id, user_id, amount, quantity, bid_time
+		FROM bids
+		WHERE auction_id = $1 AND status = 'VALID'
+		ORDER BY user_id, bid_time DESC
+	),
+	allocation AS (
+		SELECT s.id, s.user_id, s.amount, s.quantity, s.bid_time,
+			GREATEST(0, LEAST(s.quantity,
+				a.quantity - (SUM(s.quantity) OVER (ORDER BY s.amount DESC, s.bid_time ASC ROWS UNBOUNDED PRECEDING) - s.quantity)
+			)) AS units_won
+		FROM standing s
+		JOIN auctions a ON a.id = $1
+	)`
+
 type BidRepository struct {
 	db *sql.DB
 }
@@ -22,17 +45,25 @@ func NewBidRepository(db *sql.DB) *BidRepository {
 
 func (r *BidRepository) Create(ctx context.Context, tx *sql.Tx, bid *dto.CreateBidRequest, userID uuid.UUID) (*dto.ResponseBid, error) {
 	query := `
-		INSERT INTO bids (id, auction_id, user_id, amount, bid_time)
-		VALUES (gen_random_uuid(), $1, $2, $3, NOW())
-		RETURNING id, auction_id, user_id, amount, bid_time
+		INSERT INTO bids (id, auction_id, user_id, amount, quantity, is_auto, bid_time)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, FALSE, clock_timestamp())
+		RETURNING id, auction_id, user_id, amount, quantity, is_auto, status, bid_time
 	`
+	quantity := 1
+	if bid.Quantity > 0 {
+		quantity = bid.Quantity
+	}
+
 	var response dto.ResponseBid
 	var bidTime time.Time
-	err := tx.QueryRowContext(ctx, query, bid.AuctionID, userID, bid.Amount).Scan(
+	err := tx.QueryRowContext(ctx, query, bid.AuctionID, userID, bid.Amount, quantity).Scan(
 		&response.ID,
 		&response.AuctionID,
 		&response.UserID,
 		&response.Amount,
+		&response.Quantity,
+		&response.IsAuto,
+		&response.Status,
 		&bidTime,
 	)
 	if err != nil {
@@ -42,18 +73,243 @@ func (r *BidRepository) Create(ctx context.Context, tx *sql.Tx, bid *dto.CreateB
 	return &response, nil
 }
 
+// CreateAutoBid records a bid placed by the proxy engine on behalf of userID
+// and returns its ID.
+func (r *BidRepository) CreateAutoBid(ctx context.Context, tx *sql.Tx, auctionID, userID uuid.UUID, amount float64) (uuid.UUID, error) {
+	query := `
+		INSERT INTO bids (id, auction_id, user_id, amount, is_auto, bid_time)
+		VALUES (gen_random_uuid(), $1, $2, $3, TRUE, clock_timestamp())
+		RETURNING id
+	`
+	var bidID uuid.UUID
+	if err := tx.QueryRowContext(ctx, query, auctionID, userID, amount).Scan(&bidID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create auto bid: %w", err)
+	}
+	return bidID, nil
+}
+
+func (r *BidRepository) HasUserBid(ctx context.Context, tx *sql.Tx, auctionID, userID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM bids WHERE auction_id = $1 AND user_id = $2 AND status = 'VALID')`
+	var exists bool
+	if err := tx.QueryRowContext(ctx, query, auctionID, userID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check existing bid: %w", err)
+	}
+	return exists, nil
+}
+
+// GetForUpdate reads a bid and locks it until tx ends.
+func (r *BidRepository) GetForUpdate(ctx context.Context, tx *sql.Tx, bidID uuid.UUID) (*models.Bid, error) {
+	query := `
+		SELECT id, auction_id, user_id, amount, quantity, is_auto, status, voided_at, voided_by, void_reason, bid_time
+		FROM bids
+		WHERE id = $1
+		FOR UPDATE
+	`
+	var bid models.Bid
+	err := tx.QueryRowContext(ctx, query, bidID).Scan(
+		&bid.ID,
+		&bid.AuctionID,
+		&bid.UserID,
+		&bid.Amount,
+		&bid.Quantity,
+		&bid.IsAuto,
+		&bid.Status,
+		&bid.VoidedAt,
+		&bid.VoidedBy,
+		&bid.VoidReason,
+		&bid.BidTime,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("bid not found")
+		}
+		return nil, fmt.Errorf("failed to get bid: %w", err)
+	}
+	return &bid, nil
+}
+
+// Void marks a bid retracted or cancelled. The row is kept for the record.
+func (r *BidRepository) Void(ctx context.Context, tx *sql.Tx, bidID uuid.UUID, status models.BidStatus, voidedBy uuid.UUID, reason *string) error {
+	query := `
+		UPDATE bids SET status = $1, voided_at = NOW(), voided_by = $2, void_reason = $3
+		WHERE id = $4 AND status = 'VALID'
+	`
+	if _, err := tx.ExecContext(ctx, query, status, voidedBy, reason, bidID); err != nil {
+		return fmt.Errorf("failed to void bid: %w", err)
+	}
+	return nil
+}
+
+// VoidByUser voids every valid bid userID still has on an auction, the way
+// Void does one.
+func (r *BidRepository) VoidByUser(ctx context.Context, tx *sql.Tx, auctionID, userID uuid.UUID, status models.BidStatus, voidedBy uuid.UUID, reason *string) error {
+	query := `
+		UPDATE bids SET status = $1, voided_at = NOW(), voided_by = $2, void_reason = $3
+		WHERE auction_id = $4 AND user_id = $5 AND status = 'VALID'
+	`
+	if _, err := tx.ExecContext(ctx, query, status, voidedBy, reason, auctionID, userID); err != nil {
+		return fmt.Errorf("failed to void bids: %w", err)
+	}
+	return nil
+}
+
+// GetLeadingBid returns the highest valid bid on an auction, the earliest one
+// on a tie, or nil when no valid bid is left.
+func (r *BidRepository) GetLeadingBid(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (*models.Bid, error) {
+	query := `
+		SELECT id, user_id, amount
+		FROM bids
+		WHERE auction_id = $1 AND status = 'VALID'
+		ORDER BY amount DESC, bid_time ASC
+		LIMIT 1
+	`
+	bid := models.Bid{AuctionID: auctionID, Status: models.BidValid}
+	err := tx.QueryRowContext(ctx, query, auctionID).Scan(&bid.ID, &bid.UserID, &bid.Amount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get leading bid: %w", err)
+	}
+	return &bid, nil
+}
+
+// GetStandingBid returns userID's latest valid bid on an auction, the one
+// that counts for them in a multi-quantity auction, or nil when they have
+// none.
+func (r *BidRepository) GetStandingBid(ctx context.Context, tx *sql.Tx, auctionID, userID uuid.UUID) (*models.Bid, error) {
+	query := `
+		SELECT id, amount, quantity, bid_time
+		FROM bids
+		WHERE auction_id = $1 AND user_id = $2 AND status = 'VALID'
+		ORDER BY bid_time DESC
+		LIMIT 1
+	`
+	bid := models.Bid{AuctionID: auctionID, UserID: userID, Status: models.BidValid}
+	err := tx.QueryRowContext(ctx, query, auctionID, userID).Scan(&bid.ID, &bid.Amount, &bid.Quantity, &bid.BidTime)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get standing bid: %w", err)
+	}
+	return &bid, nil
+}
+
+// ListInTheMoney returns the bidders on a multi-quantity auction whose
+// standing bid currently wins units.
+func (r *BidRepository) ListInTheMoney(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		WITH ` + bidAllocationCTE + `
+		SELECT user_id FROM allocation WHERE units_won > 0
+	`
+	rows, err := tx.QueryContext(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bidders in the money: %w", err)
+	}
+	defer rows.Close()
+
+	var users []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan bidder row: %w", err)
+		}
+		users = append(users, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return users, nil
+}
+
+// GetRunnerUpBid returns the last valid bid of the highest bidder on an ended
+// single-unit auction who is neither its winner nor anyone the item was
+// already offered to, or nil when no such bid meets the reserve.
+func (r *BidRepository) GetRunnerUpBid(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (*models.Bid, error) {
+	query := `
+		WITH latest AS (
+			SELECT DISTINCT ON (user_id) id, user_id, amount, bid_time
+			FROM bids
+			WHERE auction_id = $1 AND status = 'VALID'
+			ORDER BY user_id, bid_time DESC
+		)
+		SELECT l.id, l.user_id, l.amount, l.bid_time
+		FROM latest l
+		JOIN auctions a ON a.id = $1
+		WHERE l.user_id <> a.created_by
+			AND l.user_id IS DISTINCT FROM a.winner_id
+			AND l.amount >= COALESCE(a.reserve_price, 0)
+			AND NOT EXISTS (
+				SELECT 1 FROM second_chance_offers o
+				WHERE o.auction_id = $1 AND (o.user_id = l.user_id OR o.previous_winner_id = l.user_id)
+			)
+		ORDER BY l.amount DESC, l.bid_time ASC
+		LIMIT 1
+	`
+	bid := models.Bid{AuctionID: auctionID, Quantity: 1, Status: models.BidValid}
+	err := tx.QueryRowContext(ctx, query, auctionID).Scan(&bid.ID, &bid.UserID, &bid.Amount, &bid.BidTime)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get runner-up bid: %w", err)
+	}
+	return &bid, nil
+}
+
+// GetListBidByAuctionID lists every bid on an auction, newest first, marking
+// the ones currently in the money.
 func (r *BidRepository) GetListBidByAuctionID(ctx context.Context, auctionID uuid.UUID) ([]dto.ResponseBidWithUser, error) {
+	return listBidsByAuctionID(ctx, r.db, auctionID)
+}
+
+func listBidsByAuctionID(ctx context.Context, q querier, auctionID uuid.UUID) ([]dto.ResponseBidWithUser, error) {
 	query := `
-		SELECT b.id, b.user_id, b.amount, b.bid_time, u.name, u.email
+		WITH ` + bidAllocationCTE + `
+		SELECT b.id, b.user_id, b.amount, b.quantity, b.is_auto, b.status, b.bid_time, u.name, u.email, COALESCE(al.units_won, 0)
 		FROM bids b
 		LEFT JOIN users u ON b.user_id = u.id
+		LEFT JOIN allocation al ON al.id = b.id
 		WHERE b.auction_id = $1
 		ORDER BY b.bid_time DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query, auctionID)
+	rows, err := q.QueryContext(ctx, query, auctionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get list bid by auction ID: %w", err)
 	}
+	return scanBidsWithUser(rows)
+}
+
+// GetListBidByIDs lists the given bids on an auction, newest first, the same
+// way GetListBidByAuctionID does.
+func (r *BidRepository) GetListBidByIDs(ctx context.Context, auctionID uuid.UUID, bidIDs []uuid.UUID) ([]dto.ResponseBidWithUser, error) {
+	query := `
+		WITH ` + bidAllocationCTE + `
+		SELECT b.id, b.user_id, b.amount, b.quantity, b.is_auto, b.status, b.bid_time, u.name, u.email, COALESCE(al.units_won, 0)
+		FROM bids b
+		LEFT JOIN users u ON b.user_id = u.id
+		LEFT JOIN allocation al ON al.id = b.id
+		WHERE b.auction_id = $1 AND b.id = ANY($2)
+		ORDER BY b.bid_time DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, auctionID, pq.Array(bidIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list bid by IDs: %w", err)
+	}
+	return scanBidsWithUser(rows)
+}
+
+// CountByAuctionID counts every bid on an auction, voided ones included.
+func (r *BidRepository) CountByAuctionID(ctx context.Context, auctionID uuid.UUID) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM bids WHERE auction_id = $1`, auctionID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count bids: %w", err)
+	}
+	return count, nil
+}
+
+func scanBidsWithUser(rows *sql.Rows) ([]dto.ResponseBidWithUser, error) {
 	defer rows.Close()
 
 	var response []dto.ResponseBidWithUser
@@ -64,12 +320,17 @@ func (r *BidRepository) GetListBidByAuctionID(ctx context.Context, auctionID uui
 			&bid.ID,
 			&bid.UserID,
 			&bid.Amount,
+			&bid.Quantity,
+			&bid.IsAuto,
+			&bid.Status,
 			&bidTime,
 			&bid.User.Name,
 			&bid.User.Email,
+			&bid.UnitsWon,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan bid row: %w", err)
 		}
+		bid.InTheMoney = bid.UnitsWon > 0
 		bid.CreatedAt = bidTime.Format(time.RFC3339)
 		response = append(response, bid)
 	}
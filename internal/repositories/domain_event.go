@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rebid/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type DomainEventRepository struct {
+	db *sql.DB
+}
+
+func NewDomainEventRepository(db *sql.DB) *DomainEventRepository {
+	return &DomainEventRepository{
+		db: db,
+	}
+}
+
+// Record writes a domain event and one pending delivery per sink inside the
+// caller's transaction, so it is only published if the change it reports
+// commits. The event takes the auction's next sequence number, which locks
+// the auction row until tx ends.
+func (r *DomainEventRepository) Record(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, change string, bidIDs []uuid.UUID, sinks []string) error {
+	query := `
+		WITH bumped AS (
+			UPDATE auctions SET event_seq = event_seq + 1
+			WHERE id = $1
+			RETURNING event_seq
+		), event AS (
+			INSERT INTO domain_events (id, auction_id, auction_seq, change, bid_ids, created_at)
+			SELECT gen_random_uuid(), $1, bumped.event_seq, $2, $3, NOW()
+			FROM bumped
+			RETURNING id
+		)
+		INSERT INTO domain_event_deliveries (event_id, sink, status, attempts, next_attempt_at)
+		SELECT event.id, sink, 'PENDING', 0, NOW()
+		FROM event, UNNEST($4::text[]) AS sink
+	`
+	if bidIDs == nil {
+		bidIDs = []uuid.UUID{}
+	}
+	if _, err := tx.ExecContext(ctx, query, auctionID, change, pq.Array(bidIDs), pq.Array(sinks)); err != nil {
+		return fmt.Errorf("failed to record domain event: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue takes up to limit pending deliveries that are due, oldest event
+// first, and leases them for lease like NotificationRepository.ClaimDue.
+func (r *DomainEventRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.DomainEventDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT d.event_id, d.sink
+			FROM domain_event_deliveries d
+			JOIN domain_events e ON e.id = d.event_id
+			WHERE d.status = 'PENDING' AND d.next_attempt_at <= NOW()
+			ORDER BY e.seq
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		), claimed AS (
+			UPDATE domain_event_deliveries d
+			SET attempts = d.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+			FROM due
+			WHERE d.event_id = due.event_id AND d.sink = due.sink
+			RETURNING d.event_id, d.sink, d.status, d.attempts, d.next_attempt_at
+		)
+		SELECT c.event_id, e.seq, e.auction_id, e.auction_seq, e.change, e.bid_ids, c.sink, c.status, c.attempts, c.next_attempt_at, e.created_at
+		FROM claimed c
+		JOIN domain_events e ON e.id = c.event_id
+		ORDER BY e.seq
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim domain events: %w", err)
+	}
+	defer rows.Close()
+
+	var due []models.DomainEventDelivery
+	for rows.Next() {
+		var d models.DomainEventDelivery
+		err := rows.Scan(
+			&d.EventID,
+			&d.Seq,
+			&d.AuctionID,
+			&d.AuctionSeq,
+			&d.Change,
+			pq.Array(&d.BidIDs),
+			&d.Sink,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan domain event row: %w", err)
+		}
+		due = append(due, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return due, nil
+}
+
+func (r *DomainEventRepository) MarkPublished(ctx context.Context, eventID uuid.UUID, sink string) error {
+	query := `
+		UPDATE domain_event_deliveries SET status = 'DELIVERED', published_at = NOW(), last_error = NULL
+		WHERE event_id = $1 AND sink = $2
+	`
+	if _, err := r.db.ExecContext(ctx, query, eventID, sink); err != nil {
+		return fmt.Errorf("failed to mark domain event published: %w", err)
+	}
+	return nil
+}
+
+// MarkRetry records a failed attempt and schedules the next one at
+// nextAttempt.
+func (r *DomainEventRepository) MarkRetry(ctx context.Context, eventID uuid.UUID, sink, lastError string, nextAttempt time.Time) error {
+	query := `UPDATE domain_event_deliveries SET last_error = $1, next_attempt_at = $2 WHERE event_id = $3 AND sink = $4`
+	if _, err := r.db.ExecContext(ctx, query, lastError, nextAttempt, eventID, sink); err != nil {
+		return fmt.Errorf("failed to schedule domain event retry: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed gives up on a delivery after its last attempt.
+func (r *DomainEventRepository) MarkFailed(ctx context.Context, eventID uuid.UUID, sink, lastError string) error {
+	query := `UPDATE domain_event_deliveries SET status = 'FAILED', last_error = $1 WHERE event_id = $2 AND sink = $3`
+	if _, err := r.db.ExecContext(ctx, query, lastError, eventID, sink); err != nil {
+		return fmt.Errorf("failed to mark domain event failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,314 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type LedgerRepository struct {
+	db *sql.DB
+}
+
+func NewLedgerRepository(db *sql.DB) *LedgerRepository {
+	return &LedgerRepository{
+		db: db,
+	}
+}
+
+// Transfer posts t as a debit of amount from the user's from account and a
+// matching credit to their to account; FUNDING is always the system account.
+// A user account that would go negative fails with "insufficient credit".
+func (r *LedgerRepository) Transfer(ctx context.Context, tx *sql.Tx, t *models.LedgerTransaction, from, to models.LedgerAccountKind, amount float64) error {
+	if err := r.ensureAccounts(ctx, tx, t.UserID); err != nil {
+		return err
+	}
+	fromID, err := r.accountID(ctx, tx, t.UserID, from)
+	if err != nil {
+		return err
+	}
+	toID, err := r.accountID(ctx, tx, t.UserID, to)
+	if err != nil {
+		return err
+	}
+
+	// Lock both accounts in id order so concurrent transfers between the same
+	// pair never deadlock.
+	lockQuery := `SELECT id FROM ledger_accounts WHERE id IN ($1, $2) ORDER BY id FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, lockQuery, fromID, toID)
+	if err != nil {
+		return fmt.Errorf("failed to lock ledger accounts: %w", err)
+	}
+	rows.Close()
+
+	txQuery := `
+		INSERT INTO ledger_transactions (id, kind, user_id, auction_id, created_by, memo, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, NOW())
+		RETURNING id, created_at
+	`
+	if err := tx.QueryRowContext(ctx, txQuery, t.Kind, t.UserID, t.AuctionID, t.CreatedBy, t.Memo).Scan(&t.ID, &t.CreatedAt); err != nil {
+		return fmt.Errorf("failed to create ledger transaction: %w", err)
+	}
+
+	entryQuery := `
+		INSERT INTO ledger_entries (id, transaction_id, account_id, amount, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, NOW()), (gen_random_uuid(), $1, $4, $5, NOW())
+	`
+	if _, err := tx.ExecContext(ctx, entryQuery, t.ID, fromID, -amount, toID, amount); err != nil {
+		return fmt.Errorf("failed to create ledger entries: %w", err)
+	}
+
+	balanceQuery := `UPDATE ledger_accounts SET balance = balance + $1, updated_at = NOW() WHERE id = $2`
+	for _, change := range []struct {
+		id     uuid.UUID
+		amount float64
+	}{{fromID, -amount}, {toID, amount}} {
+		if _, err := tx.ExecContext(ctx, balanceQuery, change.amount, change.id); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Constraint == "non_negative_balance" {
+				return fmt.Errorf("insufficient credit")
+			}
+			return fmt.Errorf("failed to update ledger balance: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *LedgerRepository) ensureAccounts(ctx context.Context, tx *sql.Tx, userID uuid.UUID) error {
+	query := `
+		INSERT INTO ledger_accounts (id, user_id, kind, created_at, updated_at)
+		VALUES (gen_random_uuid(), $1, 'AVAILABLE', NOW(), NOW()), (gen_random_uuid(), $1, 'HELD', NOW(), NOW())
+		ON CONFLICT (user_id, kind) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("user not found")
+		}
+		return fmt.Errorf("failed to create ledger accounts: %w", err)
+	}
+	return nil
+}
+
+func (r *LedgerRepository) accountID(ctx context.Context, tx *sql.Tx, userID uuid.UUID, kind models.LedgerAccountKind) (uuid.UUID, error) {
+	query := `
+		SELECT id FROM ledger_accounts
+		WHERE kind = $1 AND (user_id = $2 OR (kind = 'FUNDING' AND user_id IS NULL))
+	`
+	var id uuid.UUID
+	if err := tx.QueryRowContext(ctx, query, kind, userID).Scan(&id); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get %s ledger account: %w", kind, err)
+	}
+	return id, nil
+}
+
+// GetBalances returns a user's available and held credit. Users who never
+// had credit have none of either.
+func (r *LedgerRepository) GetBalances(ctx context.Context, userID uuid.UUID) (float64, float64, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(balance) FILTER (WHERE kind = 'AVAILABLE'), 0),
+			COALESCE(SUM(balance) FILTER (WHERE kind = 'HELD'), 0)
+		FROM ledger_accounts
+		WHERE user_id = $1
+	`
+	var available, held float64
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&available, &held); err != nil {
+		return 0, 0, fmt.Errorf("failed to get ledger balances: %w", err)
+	}
+	return available, held, nil
+}
+
+// GetAvailable reads a user's available credit inside tx.
+func (r *LedgerRepository) GetAvailable(ctx context.Context, tx *sql.Tx, userID uuid.UUID) (float64, error) {
+	query := `SELECT COALESCE(SUM(balance), 0) FROM ledger_accounts WHERE user_id = $1 AND kind = 'AVAILABLE'`
+	var available float64
+	if err := tx.QueryRowContext(ctx, query, userID).Scan(&available); err != nil {
+		return 0, fmt.Errorf("failed to get available credit: %w", err)
+	}
+	return available, nil
+}
+
+func (r *LedgerRepository) GetHold(ctx context.Context, tx *sql.Tx, auctionID, userID uuid.UUID) (float64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM credit_holds WHERE auction_id = $1 AND user_id = $2`
+	var amount float64
+	if err := tx.QueryRowContext(ctx, query, auctionID, userID).Scan(&amount); err != nil {
+		return 0, fmt.Errorf("failed to get credit hold: %w", err)
+	}
+	return amount, nil
+}
+
+// ListHolds returns the credit held on an auction by user, locking the rows
+// until tx ends.
+func (r *LedgerRepository) ListHolds(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (map[uuid.UUID]float64, error) {
+	query := `SELECT user_id, amount FROM credit_holds WHERE auction_id = $1 FOR UPDATE`
+	return scanUserAmounts(tx.QueryContext(ctx, query, auctionID))
+}
+
+// SetHold records how much a user has held on an auction; zero removes the
+// hold. The money itself moves with Transfer.
+func (r *LedgerRepository) SetHold(ctx context.Context, tx *sql.Tx, auctionID, userID uuid.UUID, amount float64) error {
+	if amount <= 0 {
+		query := `DELETE FROM credit_holds WHERE auction_id = $1 AND user_id = $2`
+		if _, err := tx.ExecContext(ctx, query, auctionID, userID); err != nil {
+			return fmt.Errorf("failed to delete credit hold: %w", err)
+		}
+		return nil
+	}
+
+	query := `
+		INSERT INTO credit_holds (auction_id, user_id, amount, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (auction_id, user_id)
+		DO UPDATE SET amount = EXCLUDED.amount, updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, query, auctionID, userID, amount); err != nil {
+		return fmt.Errorf("failed to set credit hold: %w", err)
+	}
+	return nil
+}
+
+// DesiredHolds works out, from the auction's current state, how much credit
+// each bidder should have held on a deposit auction:
+//   - a running single-unit auction holds its leader's commitment, the
+//     current price or their proxy ceiling if higher;
+//   - a running sealed-bid auction holds every valid bid;
+//   - a running multi-quantity auction holds every bid in the money for all
+//     the units it asks for;
+//   - an ended auction holds what each winner owes at the hammer price until
+//     their payment is captured.
+//
+// Auctions without a deposit, and cancelled ones, hold nothing.
+func (r *LedgerRepository) DesiredHolds(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (map[uuid.UUID]float64, error) {
+	query := `
+		WITH ` + bidAllocationCTE + `,
+		auction AS (
+			SELECT * FROM auctions WHERE id = $1 AND deposit_required
+		)
+		SELECT a.current_bidder_id, GREATEST(a.current_price, COALESCE(p.max_amount, 0))
+		FROM auction a
+		LEFT JOIN proxy_bids p ON p.auction_id = a.id AND p.user_id = a.current_bidder_id
+		WHERE a.status = 'ACTIVE' AND a.quantity = 1
+			AND a.auction_type NOT IN ('SEALED_FIRST_PRICE', 'SEALED_SECOND_PRICE')
+			AND a.current_bidder_id IS NOT NULL
+		UNION ALL
+		SELECT b.user_id, b.amount
+		FROM auction a
+		JOIN bids b ON b.auction_id = a.id AND b.status = 'VALID'
+		WHERE a.status = 'ACTIVE' AND a.auction_type IN ('SEALED_FIRST_PRICE', 'SEALED_SECOND_PRICE')
+		UNION ALL
+		SELECT al.user_id, al.amount * al.quantity
+		FROM auction a
+		JOIN allocation al ON al.units_won > 0
+		WHERE a.status = 'ACTIVE' AND a.quantity > 1
+		UNION ALL
+		SELECT res.winner_id, res.hammer_price * res.quantity
+		FROM auction a
+		JOIN auction_results res ON res.auction_id = a.id AND res.outcome = 'SOLD'
+		WHERE a.status = 'ENDED' AND res.payment_status IN ('PENDING', 'AUTHORIZED', 'FAILED')
+	`
+	return scanUserAmounts(tx.QueryContext(ctx, query, auctionID))
+}
+
+func scanUserAmounts(rows *sql.Rows, err error) (map[uuid.UUID]float64, error) {
+	if err != nil {
+		return nil, fmt.Errorf("failed to query credit holds: %w", err)
+	}
+	defer rows.Close()
+
+	amounts := map[uuid.UUID]float64{}
+	for rows.Next() {
+		var userID uuid.UUID
+		var amount float64
+		if err := rows.Scan(&userID, &amount); err != nil {
+			return nil, fmt.Errorf("failed to scan credit hold row: %w", err)
+		}
+		amounts[userID] += amount
+	}
+	return amounts, rows.Err()
+}
+
+// ListHoldsByUser returns the credit a user has held, newest first.
+func (r *LedgerRepository) ListHoldsByUser(ctx context.Context, userID uuid.UUID) ([]dto.ResponseCreditHold, error) {
+	query := `
+		SELECT h.auction_id, a.description, h.amount, h.updated_at
+		FROM credit_holds h
+		JOIN auctions a ON h.auction_id = a.id
+		WHERE h.user_id = $1
+		ORDER BY h.updated_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list credit holds: %w", err)
+	}
+	defer rows.Close()
+
+	holds := []dto.ResponseCreditHold{}
+	for rows.Next() {
+		var hold dto.ResponseCreditHold
+		var updatedAt time.Time
+		if err := rows.Scan(&hold.AuctionID, &hold.Description, &hold.Amount, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan credit hold row: %w", err)
+		}
+		hold.UpdatedAt = updatedAt.Format(time.RFC3339)
+		holds = append(holds, hold)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return holds, nil
+}
+
+// ListEntries returns the entries posted to a user's accounts, newest first.
+func (r *LedgerRepository) ListEntries(ctx context.Context, userID uuid.UUID, limit int) ([]dto.ResponseLedgerEntry, error) {
+	query := `
+		SELECT e.id, t.id, t.kind, a.kind, e.amount, t.auction_id, t.memo, e.created_at
+		FROM ledger_entries e
+		JOIN ledger_accounts a ON e.account_id = a.id
+		JOIN ledger_transactions t ON e.transaction_id = t.id
+		WHERE a.user_id = $1
+		ORDER BY e.created_at DESC, a.kind ASC
+	`
+	args := []interface{}{userID}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ledger entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []dto.ResponseLedgerEntry{}
+	for rows.Next() {
+		var entry dto.ResponseLedgerEntry
+		var createdAt time.Time
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.TransactionID,
+			&entry.Kind,
+			&entry.Account,
+			&entry.Amount,
+			&entry.AuctionID,
+			&entry.Memo,
+			&createdAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan ledger entry row: %w", err)
+		}
+		entry.CreatedAt = createdAt.Format(time.RFC3339)
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{
+		db: db,
+	}
+}
+
+// Enqueue writes a notification to the outbox once per channel, inside the
+// caller's transaction so it is only sent if the change it reports commits.
+func (r *NotificationRepository) Enqueue(ctx context.Context, tx *sql.Tx, userID uuid.UUID, auctionID *uuid.UUID, kind, subject, body string, channels []string) error {
+	query := `
+		INSERT INTO notification_outbox (id, user_id, auction_id, channel, kind, subject, body, status, attempts, next_attempt_at, created_at)
+		SELECT gen_random_uuid(), $1, $2, channel, $3, $4, $5, 'PENDING', 0, NOW(), NOW()
+		FROM UNNEST($6::text[]) AS channel
+	`
+	if _, err := tx.ExecContext(ctx, query, userID, auctionID, kind, subject, body, pq.Array(channels)); err != nil {
+		return fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue takes up to limit pending notifications that are due and leases
+// them for lease: their next attempt moves that far ahead, so a dispatcher
+// that dies mid-delivery only delays them. Rows another dispatcher is
+// claiming are skipped.
+func (r *NotificationRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]models.OutboxNotification, error) {
+	query := `
+		UPDATE notification_outbox o
+		SET attempts = o.attempts + 1, next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM (
+			SELECT id FROM notification_outbox
+			WHERE status = 'PENDING' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE o.id = due.id
+		RETURNING o.id, o.user_id, o.auction_id, o.channel, o.kind, o.subject, o.body, o.status, o.attempts, o.next_attempt_at, o.created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var due []models.OutboxNotification
+	for rows.Next() {
+		var n models.OutboxNotification
+		err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.AuctionID,
+			&n.Channel,
+			&n.Kind,
+			&n.Subject,
+			&n.Body,
+			&n.Status,
+			&n.Attempts,
+			&n.NextAttemptAt,
+			&n.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification row: %w", err)
+		}
+		due = append(due, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return due, nil
+}
+
+func (r *NotificationRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE notification_outbox SET status = 'DELIVERED', delivered_at = NOW(), last_error = NULL WHERE id = $1`
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to mark notification delivered: %w", err)
+	}
+	return nil
+}
+
+// MarkRetry records a failed attempt and schedules the next one at
+// nextAttempt.
+func (r *NotificationRepository) MarkRetry(ctx context.Context, id uuid.UUID, lastError string, nextAttempt time.Time) error {
+	query := `UPDATE notification_outbox SET last_error = $1, next_attempt_at = $2 WHERE id = $3`
+	if _, err := r.db.ExecContext(ctx, query, lastError, nextAttempt, id); err != nil {
+		return fmt.Errorf("failed to schedule notification retry: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed gives up on a notification after its last attempt.
+func (r *NotificationRepository) MarkFailed(ctx context.Context, id uuid.UUID, lastError string) error {
+	query := `UPDATE notification_outbox SET status = 'FAILED', last_error = $1 WHERE id = $2`
+	if _, err := r.db.ExecContext(ctx, query, lastError, id); err != nil {
+		return fmt.Errorf("failed to mark notification failed: %w", err)
+	}
+	return nil
+}
+
+// AddToInbox stores an in-app notification. Storing the same id again is a
+// no-op, so a redelivered notification shows up once.
+func (r *NotificationRepository) AddToInbox(ctx context.Context, id, userID uuid.UUID, kind, subject, body string, auctionID *uuid.UUID) error {
+	query := `
+		INSERT INTO notifications (id, user_id, auction_id, kind, subject, body, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (id) DO NOTHING
+	`
+	if _, err := r.db.ExecContext(ctx, query, id, userID, auctionID, kind, subject, body); err != nil {
+		return fmt.Errorf("failed to add notification to inbox: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) GetUserEmail(ctx context.Context, userID uuid.UUID) (string, error) {
+	var email string
+	err := r.db.QueryRowContext(ctx, `SELECT email FROM users WHERE id = $1`, userID).Scan(&email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("user not found")
+		}
+		return "", fmt.Errorf("failed to get user email: %w", err)
+	}
+	return email, nil
+}
+
+// ListInbox returns a user's in-app notifications, newest first, keeping
+// only the unread ones when filter.Unread is set.
+func (r *NotificationRepository) ListInbox(ctx context.Context, userID uuid.UUID, filter *dto.FilterNotification) ([]models.Notification, error) {
+	query := `
+		SELECT id, user_id, auction_id, kind, subject, body, read_at, created_at
+		FROM notifications
+		WHERE user_id = $1 AND ($2 = FALSE OR read_at IS NULL)
+		ORDER BY created_at DESC, id
+	`
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+	rows, err := r.db.QueryContext(ctx, query, userID, filter.Unread)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.AuctionID,
+			&n.Kind,
+			&n.Subject,
+			&n.Body,
+			&n.ReadAt,
+			&n.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification row: %w", err)
+		}
+		notifications = append(notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks one of a user's notifications read and returns it. Reading
+// it again keeps the time it was first read.
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID uuid.UUID) (*models.Notification, error) {
+	query := `
+		UPDATE notifications SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+		RETURNING id, user_id, auction_id, kind, subject, body, read_at, created_at
+	`
+	var n models.Notification
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(
+		&n.ID,
+		&n.UserID,
+		&n.AuctionID,
+		&n.Kind,
+		&n.Subject,
+		&n.Body,
+		&n.ReadAt,
+		&n.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("notification not found")
+		}
+		return nil, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return &n, nil
+}
+
+// MarkAllRead marks every unread notification of a user read and returns
+// how many there were.
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return int(n), nil
+}
+
+// EnqueueForWatchers writes a notification to the outbox, once per channel,
+// for everyone watching an auction, inside the caller's transaction.
+func (r *NotificationRepository) EnqueueForWatchers(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, kind, subject, body string, channels []string) error {
+	query := `
+		INSERT INTO notification_outbox (id, user_id, auction_id, channel, kind, subject, body, status, attempts, next_attempt_at, created_at)
+		SELECT gen_random_uuid(), w.user_id, w.auction_id, channel, $2, $3, $4, 'PENDING', 0, NOW(), NOW()
+		FROM watchlist w
+		CROSS JOIN UNNEST($5::text[]) AS channel
+		WHERE w.auction_id = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, auctionID, kind, subject, body, pq.Array(channels)); err != nil {
+		return fmt.Errorf("failed to enqueue watcher notifications: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const paymentResultColumns = `id, auction_id, seller_id, winner_id, outcome, hammer_price, quantity, buyer_fee, seller_fee, payment_status, payment_provider_ref, closed_at, created_at`
+
+type PaymentRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentRepository(db *sql.DB) *PaymentRepository {
+	return &PaymentRepository{
+		db: db,
+	}
+}
+
+// LockWinnerResult locks the sold result of auctionID that winnerID owes.
+func (r *PaymentRepository) LockWinnerResult(ctx context.Context, tx *sql.Tx, auctionID, winnerID uuid.UUID) (*models.AuctionResult, error) {
+	query := `
+		SELECT ` + paymentResultColumns + `
+		FROM auction_results
+		WHERE auction_id = $1 AND winner_id = $2 AND outcome = 'SOLD'
+		FOR UPDATE
+	`
+	return scanPaymentResult(tx.QueryRowContext(ctx, query, auctionID, winnerID))
+}
+
+func (r *PaymentRepository) LockResult(ctx context.Context, tx *sql.Tx, resultID uuid.UUID) (*models.AuctionResult, error) {
+	query := `
+		SELECT ` + paymentResultColumns + `
+		FROM auction_results
+		WHERE id = $1
+		FOR UPDATE
+	`
+	return scanPaymentResult(tx.QueryRowContext(ctx, query, resultID))
+}
+
+func (r *PaymentRepository) GetResult(ctx context.Context, resultID uuid.UUID) (*models.AuctionResult, error) {
+	query := `
+		SELECT ` + paymentResultColumns + `
+		FROM auction_results
+		WHERE id = $1
+	`
+	return scanPaymentResult(r.db.QueryRowContext(ctx, query, resultID))
+}
+
+// FindResultByProviderRef returns the result a provider payment belongs to,
+// without locking it.
+func (r *PaymentRepository) FindResultByProviderRef(ctx context.Context, providerRef string) (*models.AuctionResult, error) {
+	query := `
+		SELECT ` + paymentResultColumns + `
+		FROM auction_results
+		WHERE payment_provider_ref = $1
+	`
+	return scanPaymentResult(r.db.QueryRowContext(ctx, query, providerRef))
+}
+
+func scanPaymentResult(row rowScanner) (*models.AuctionResult, error) {
+	var result models.AuctionResult
+	err := row.Scan(
+		&result.ID,
+		&result.AuctionID,
+		&result.SellerID,
+		&result.WinnerID,
+		&result.Outcome,
+		&result.HammerPrice,
+		&result.Quantity,
+		&result.BuyerFee,
+		&result.SellerFee,
+		&result.PaymentStatus,
+		&result.PaymentProviderRef,
+		&result.ClosedAt,
+		&result.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("auction result not found")
+		}
+		return nil, fmt.Errorf("failed to get auction result: %w", err)
+	}
+	return &result, nil
+}
+
+// RecordStatus moves a locked result to a new payment status and writes the
+// audit row for it. A nil providerRef keeps the result's current reference.
+func (r *PaymentRepository) RecordStatus(ctx context.Context, tx *sql.Tx, result *models.AuctionResult, t *models.PaymentStatusTransition) error {
+	query := `
+		UPDATE auction_results
+		SET payment_status = $2, payment_provider_ref = COALESCE($3, payment_provider_ref)
+		WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, query, result.ID, t.ToStatus, t.ProviderRef); err != nil {
+		return fmt.Errorf("failed to update payment status: %w", err)
+	}
+
+	query = `
+		INSERT INTO payment_status_transitions (id, result_id, from_status, to_status, source, provider_ref, amount, detail, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, $7, NOW())
+	`
+	if _, err := tx.ExecContext(ctx, query, result.ID, t.FromStatus, t.ToStatus, t.Source, t.ProviderRef, t.Amount, t.Detail); err != nil {
+		return fmt.Errorf("failed to record payment status transition: %w", err)
+	}
+
+	result.PaymentStatus = &t.ToStatus
+	if t.ProviderRef != nil {
+		result.PaymentProviderRef = t.ProviderRef
+	}
+	return nil
+}
+
+// AttachProviderRef stores the provider's reference on a result whose
+// checkout is under way, so its webhooks can find it.
+func (r *PaymentRepository) AttachProviderRef(ctx context.Context, resultID uuid.UUID, providerRef string) error {
+	query := `
+		UPDATE auction_results
+		SET payment_provider_ref = $2
+		WHERE id = $1 AND payment_status = 'AUTHORIZED'
+	`
+	res, err := r.db.ExecContext(ctx, query, resultID, providerRef)
+	if err != nil {
+		return fmt.Errorf("failed to attach payment provider ref: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to attach payment provider ref: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("payment is no longer authorized")
+	}
+	return nil
+}
+
+// ListTransitions returns a result's payment history, oldest first.
+func (r *PaymentRepository) ListTransitions(ctx context.Context, resultID uuid.UUID) ([]dto.ResponsePaymentTransition, error) {
+	query := `
+		SELECT id, from_status, to_status, source, provider_ref, amount, detail, created_at
+		FROM payment_status_transitions
+		WHERE result_id = $1
+		ORDER BY created_at ASC, id ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, resultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payment transitions: %w", err)
+	}
+	defer rows.Close()
+
+	response := []dto.ResponsePaymentTransition{}
+	for rows.Next() {
+		var t dto.ResponsePaymentTransition
+		var createdAt time.Time
+		if err := rows.Scan(&t.ID, &t.FromStatus, &t.ToStatus, &t.Source, &t.ProviderRef, &t.Amount, &t.Detail, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan payment transition row: %w", err)
+		}
+		t.CreatedAt = createdAt.Format(time.RFC3339)
+		response = append(response, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return response, nil
+}
+
+// MarkWebhookEvent records a provider event ID and reports whether it was
+// seen for the first time.
+func (r *PaymentRepository) MarkWebhookEvent(ctx context.Context, tx *sql.Tx, eventID string) (bool, error) {
+	query := `
+		INSERT INTO payment_webhook_events (id, received_at)
+		VALUES ($1, NOW())
+		ON CONFLICT (id) DO NOTHING
+	`
+	res, err := tx.ExecContext(ctx, query, eventID)
+	if err != nil {
+		return false, fmt.Errorf("failed to record webhook event: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to record webhook event: %w", err)
+	}
+	return n == 1, nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rebid/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type ProxyBidRepository struct {
+	db *sql.DB
+}
+
+func NewProxyBidRepository(db *sql.DB) *ProxyBidRepository {
+	return &ProxyBidRepository{
+		db: db,
+	}
+}
+
+func (r *ProxyBidRepository) Upsert(ctx context.Context, tx *sql.Tx, auctionID, userID uuid.UUID, maxAmount float64) error {
+	query := `
+		INSERT INTO proxy_bids (id, auction_id, user_id, max_amount, created_at, updated_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, NOW(), NOW())
+		ON CONFLICT (auction_id, user_id)
+		DO UPDATE SET max_amount = EXCLUDED.max_amount, updated_at = NOW()
+	`
+	if _, err := tx.ExecContext(ctx, query, auctionID, userID, maxAmount); err != nil {
+		return fmt.Errorf("failed to save proxy bid: %w", err)
+	}
+	return nil
+}
+
+// GetByAuctionID returns the ceilings for an auction, highest first. Equal
+// ceilings are ordered by when they were set so the earlier one wins.
+func (r *ProxyBidRepository) GetByAuctionID(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) ([]models.ProxyBid, error) {
+	query := `
+		SELECT id, auction_id, user_id, max_amount, created_at, updated_at
+		FROM proxy_bids
+		WHERE auction_id = $1
+		ORDER BY max_amount DESC, updated_at ASC
+	`
+	rows, err := tx.QueryContext(ctx, query, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get proxy bids: %w", err)
+	}
+	defer rows.Close()
+
+	var proxies []models.ProxyBid
+	for rows.Next() {
+		var p models.ProxyBid
+		if err := rows.Scan(
+			&p.ID,
+			&p.AuctionID,
+			&p.UserID,
+			&p.MaxAmount,
+			&p.CreatedAt,
+			&p.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan proxy bid: %w", err)
+		}
+		proxies = append(proxies, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+
+	return proxies, nil
+}
+
+func (r *ProxyBidRepository) DeleteByAuctionID(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM proxy_bids WHERE auction_id = $1`, auctionID); err != nil {
+		return fmt.Errorf("failed to delete proxy bids: %w", err)
+	}
+	return nil
+}
+
+func (r *ProxyBidRepository) DeleteByUser(ctx context.Context, tx *sql.Tx, auctionID, userID uuid.UUID) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM proxy_bids WHERE auction_id = $1 AND user_id = $2`, auctionID, userID); err != nil {
+		return fmt.Errorf("failed to delete proxy bid: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const secondChanceOfferColumns = `id, auction_id, result_id, bid_id, user_id, previous_winner_id, amount, status, source, created_by, expires_at, responded_at, created_at`
+
+type SecondChanceOfferRepository struct {
+	db *sql.DB
+}
+
+func NewSecondChanceOfferRepository(db *sql.DB) *SecondChanceOfferRepository {
+	return &SecondChanceOfferRepository{
+		db: db,
+	}
+}
+
+// LockSoldResult locks the settlement of a sold single-unit auction.
+func (r *SecondChanceOfferRepository) LockSoldResult(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (*models.AuctionResult, error) {
+	query := `
+		SELECT ` + paymentResultColumns + `
+		FROM auction_results
+		WHERE auction_id = $1 AND outcome = 'SOLD' AND quantity = 1
+		FOR UPDATE
+	`
+	return scanPaymentResult(tx.QueryRowContext(ctx, query, auctionID))
+}
+
+func (r *SecondChanceOfferRepository) Create(ctx context.Context, tx *sql.Tx, offer *models.SecondChanceOffer) error {
+	query := `
+		INSERT INTO second_chance_offers (id, auction_id, result_id, bid_id, user_id, previous_winner_id, amount, status, source, created_by, expires_at, created_at)
+		VALUES (gen_random_uuid(), $1, $2, $3, $4, $5, $6, 'PENDING', $7, $8, $9, NOW())
+		RETURNING id, status, created_at
+	`
+	err := tx.QueryRowContext(ctx, query,
+		offer.AuctionID,
+		offer.ResultID,
+		offer.BidID,
+		offer.UserID,
+		offer.PreviousWinnerID,
+		offer.Amount,
+		offer.Source,
+		offer.CreatedBy,
+		offer.ExpiresAt,
+	).Scan(&offer.ID, &offer.Status, &offer.CreatedAt)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Constraint == "unique_pending_second_chance_offer" {
+			return fmt.Errorf("offer already pending")
+		}
+		return fmt.Errorf("failed to create second-chance offer: %w", err)
+	}
+	return nil
+}
+
+// HasPending reports whether an auction's item is currently offered to a
+// runner-up.
+func (r *SecondChanceOfferRepository) HasPending(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM second_chance_offers WHERE auction_id = $1 AND status = 'PENDING')`
+	var pending bool
+	if err := tx.QueryRowContext(ctx, query, auctionID).Scan(&pending); err != nil {
+		return false, fmt.Errorf("failed to check pending offers: %w", err)
+	}
+	return pending, nil
+}
+
+func (r *SecondChanceOfferRepository) GetByID(ctx context.Context, offerID uuid.UUID) (*models.SecondChanceOffer, error) {
+	query := `SELECT ` + secondChanceOfferColumns + ` FROM second_chance_offers WHERE id = $1`
+	return scanSecondChanceOffer(r.db.QueryRowContext(ctx, query, offerID))
+}
+
+func (r *SecondChanceOfferRepository) LockByID(ctx context.Context, tx *sql.Tx, offerID uuid.UUID) (*models.SecondChanceOffer, error) {
+	query := `SELECT ` + secondChanceOfferColumns + ` FROM second_chance_offers WHERE id = $1 FOR UPDATE`
+	return scanSecondChanceOffer(tx.QueryRowContext(ctx, query, offerID))
+}
+
+func scanSecondChanceOffer(row rowScanner) (*models.SecondChanceOffer, error) {
+	var offer models.SecondChanceOffer
+	err := row.Scan(
+		&offer.ID,
+		&offer.AuctionID,
+		&offer.ResultID,
+		&offer.BidID,
+		&offer.UserID,
+		&offer.PreviousWinnerID,
+		&offer.Amount,
+		&offer.Status,
+		&offer.Source,
+		&offer.CreatedBy,
+		&offer.ExpiresAt,
+		&offer.RespondedAt,
+		&offer.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("offer not found")
+		}
+		return nil, fmt.Errorf("failed to get second-chance offer: %w", err)
+	}
+	return &offer, nil
+}
+
+// Respond closes a pending offer with status.
+func (r *SecondChanceOfferRepository) Respond(ctx context.Context, tx *sql.Tx, offerID uuid.UUID, status models.OfferStatus) error {
+	query := `UPDATE second_chance_offers SET status = $1, responded_at = NOW() WHERE id = $2 AND status = 'PENDING'`
+	if _, err := tx.ExecContext(ctx, query, status, offerID); err != nil {
+		return fmt.Errorf("failed to respond to second-chance offer: %w", err)
+	}
+	return nil
+}
+
+// ExpireStale closes every pending offer past its expiry and returns them.
+func (r *SecondChanceOfferRepository) ExpireStale(ctx context.Context, tx *sql.Tx) ([]models.SecondChanceOffer, error) {
+	query := `
+		UPDATE second_chance_offers
+		SET status = 'EXPIRED', responded_at = NOW()
+		WHERE status = 'PENDING' AND expires_at <= NOW()
+		RETURNING ` + secondChanceOfferColumns + `
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire second-chance offers: %w", err)
+	}
+	defer rows.Close()
+
+	var offers []models.SecondChanceOffer
+	for rows.Next() {
+		offer, err := scanSecondChanceOffer(rows)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, *offer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return offers, nil
+}
+
+// ListLapsedAuctions returns the sold single-unit auctions whose winner has
+// not paid within deadline of winning, counted from the close or from the
+// last accepted offer, and whose item is not already on offer.
+func (r *SecondChanceOfferRepository) ListLapsedAuctions(ctx context.Context, deadline time.Duration) ([]uuid.UUID, error) {
+	query := `
+		SELECT r.auction_id
+		FROM auction_results r
+		JOIN auctions a ON a.id = r.auction_id
+		WHERE a.status = 'ENDED' AND r.outcome = 'SOLD' AND r.quantity = 1
+			AND r.payment_status IN ('PENDING', 'FAILED')
+			AND GREATEST(r.closed_at, (
+				SELECT MAX(o.responded_at) FROM second_chance_offers o
+				WHERE o.result_id = r.id AND o.status = 'ACCEPTED'
+			)) <= NOW() - make_interval(secs => $1)
+			AND NOT EXISTS (
+				SELECT 1 FROM second_chance_offers o
+				WHERE o.auction_id = r.auction_id AND o.status = 'PENDING'
+			)
+		ORDER BY r.closed_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, deadline.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lapsed auctions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan lapsed auction row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return ids, nil
+}
+
+// ListByAuctionID returns every offer made on an auction, newest first.
+func (r *SecondChanceOfferRepository) ListByAuctionID(ctx context.Context, auctionID uuid.UUID) ([]dto.ResponseSecondChanceOffer, error) {
+	return r.list(ctx, `o.auction_id = $1`, auctionID)
+}
+
+// ListByUser returns every offer made to userID, newest first.
+func (r *SecondChanceOfferRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]dto.ResponseSecondChanceOffer, error) {
+	return r.list(ctx, `o.user_id = $1`, userID)
+}
+
+func (r *SecondChanceOfferRepository) list(ctx context.Context, where string, id uuid.UUID) ([]dto.ResponseSecondChanceOffer, error) {
+	query := `
+		SELECT o.id, o.auction_id, a.description, o.user_id, o.previous_winner_id, o.amount, o.status, o.source, o.expires_at, o.responded_at, o.created_at
+		FROM second_chance_offers o
+		JOIN auctions a ON o.auction_id = a.id
+		WHERE ` + where + `
+		ORDER BY o.created_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list second-chance offers: %w", err)
+	}
+	defer rows.Close()
+
+	response := []dto.ResponseSecondChanceOffer{}
+	for rows.Next() {
+		var offer dto.ResponseSecondChanceOffer
+		var expiresAt, createdAt time.Time
+		var respondedAt *time.Time
+		err := rows.Scan(
+			&offer.ID,
+			&offer.AuctionID,
+			&offer.Description,
+			&offer.UserID,
+			&offer.PreviousWinnerID,
+			&offer.Amount,
+			&offer.Status,
+			&offer.Source,
+			&expiresAt,
+			&respondedAt,
+			&createdAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan second-chance offer row: %w", err)
+		}
+		offer.ExpiresAt = expiresAt.Format(time.RFC3339)
+		if respondedAt != nil {
+			s := respondedAt.Format(time.RFC3339)
+			offer.RespondedAt = &s
+		}
+		offer.CreatedAt = createdAt.Format(time.RFC3339)
+		response = append(response, offer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return response, nil
+}
@@ -0,0 +1,146 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"rebid/internal/dto"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type WatchlistRepository struct {
+	db *sql.DB
+}
+
+func NewWatchlistRepository(db *sql.DB) *WatchlistRepository {
+	return &WatchlistRepository{
+		db: db,
+	}
+}
+
+// Add puts an auction on a user's watchlist. Watching an auction twice is a
+// no-op.
+func (r *WatchlistRepository) Add(ctx context.Context, userID, auctionID uuid.UUID) error {
+	query := `
+		INSERT INTO watchlist (user_id, auction_id, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id, auction_id) DO NOTHING
+	`
+	if _, err := r.db.ExecContext(ctx, query, userID, auctionID); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return fmt.Errorf("auction not found")
+		}
+		return fmt.Errorf("failed to watch auction: %w", err)
+	}
+	return nil
+}
+
+func (r *WatchlistRepository) Remove(ctx context.Context, userID, auctionID uuid.UUID) error {
+	query := `DELETE FROM watchlist WHERE user_id = $1 AND auction_id = $2`
+	res, err := r.db.ExecContext(ctx, query, userID, auctionID)
+	if err != nil {
+		return fmt.Errorf("failed to unwatch auction: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to unwatch auction: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("auction not watched")
+	}
+	return nil
+}
+
+// ListByUser returns the auctions a user watches, ending soonest first.
+func (r *WatchlistRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]dto.ResponseWatchlistItem, error) {
+	query := `
+		SELECT a.id, a.description, a.status, a.current_price, a.end_time, w.created_at
+		FROM watchlist w
+		JOIN auctions a ON a.id = w.auction_id
+		WHERE w.user_id = $1
+		ORDER BY a.end_time ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlist: %w", err)
+	}
+	defer rows.Close()
+
+	response := []dto.ResponseWatchlistItem{}
+	for rows.Next() {
+		var item dto.ResponseWatchlistItem
+		var watchedAt time.Time
+		if err := rows.Scan(&item.AuctionID, &item.Description, &item.Status, &item.CurrentPrice, &item.EndTime, &watchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist row: %w", err)
+		}
+		item.WatchedAt = watchedAt.Format(time.RFC3339)
+		response = append(response, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return response, nil
+}
+
+// WatchlistReminder is an ending-soon reminder due to a watcher.
+type WatchlistReminder struct {
+	UserID      uuid.UUID
+	AuctionID   uuid.UUID
+	Description *string
+	EndTime     time.Time
+	Threshold   time.Duration
+}
+
+// ClaimDueReminders records, and returns, every reminder not yet sent for a
+// watched ACTIVE auction that has crossed one of thresholds before its
+// end_time. Recording them first means a reminder is claimed once even if
+// two workers run the same pass.
+func (r *WatchlistRepository) ClaimDueReminders(ctx context.Context, thresholds []time.Duration) ([]WatchlistReminder, error) {
+	secs := make([]int64, len(thresholds))
+	for i, t := range thresholds {
+		secs[i] = int64(t.Seconds())
+	}
+
+	query := `
+		WITH claimed AS (
+			INSERT INTO watchlist_reminders (user_id, auction_id, threshold_seconds, sent_at)
+			SELECT w.user_id, w.auction_id, t.secs, NOW()
+			FROM watchlist w
+			JOIN auctions a ON a.id = w.auction_id
+			CROSS JOIN UNNEST($1::int[]) AS t(secs)
+			WHERE a.status = 'ACTIVE' AND a.end_time > NOW()
+				AND a.end_time <= NOW() + make_interval(secs => t.secs)
+			ON CONFLICT DO NOTHING
+			RETURNING user_id, auction_id, threshold_seconds
+		)
+		SELECT c.user_id, c.auction_id, a.description, a.end_time, c.threshold_seconds
+		FROM claimed c
+		JOIN auctions a ON a.id = c.auction_id
+		ORDER BY c.auction_id, c.user_id, c.threshold_seconds
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(secs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim watchlist reminders: %w", err)
+	}
+	defer rows.Close()
+
+	var reminders []WatchlistReminder
+	for rows.Next() {
+		var rem WatchlistReminder
+		var secs int64
+		if err := rows.Scan(&rem.UserID, &rem.AuctionID, &rem.Description, &rem.EndTime, &secs); err != nil {
+			return nil, fmt.Errorf("failed to scan watchlist reminder row: %w", err)
+		}
+		rem.Threshold = time.Duration(secs) * time.Second
+		reminders = append(reminders, rem)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return reminders, nil
+}
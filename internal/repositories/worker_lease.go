@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"rebid/internal/dto"
+	"time"
+)
+
+type WorkerLeaseRepository struct {
+	db *sql.DB
+}
+
+func NewWorkerLeaseRepository(db *sql.DB) *WorkerLeaseRepository {
+	return &WorkerLeaseRepository{
+		db: db,
+	}
+}
+
+// TryLock takes the advisory lock on key for conn's session, returning false
+// when another session holds it. The lock is held until conn closes.
+func (r *WorkerLeaseRepository) TryLock(ctx context.Context, conn *sql.Conn, key int64) (bool, error) {
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	return locked, nil
+}
+
+// Acquire records nodeID as the holder of job's lock.
+func (r *WorkerLeaseRepository) Acquire(ctx context.Context, conn *sql.Conn, job string, key int64, nodeID string) error {
+	query := `
+		INSERT INTO worker_leases (job, lock_key, node_id, acquired_at, heartbeat_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (job) DO UPDATE
+		SET lock_key = EXCLUDED.lock_key, node_id = EXCLUDED.node_id, acquired_at = NOW(), heartbeat_at = NOW()
+	`
+	if _, err := conn.ExecContext(ctx, query, job, key, nodeID); err != nil {
+		return fmt.Errorf("failed to record worker lease: %w", err)
+	}
+	return nil
+}
+
+// Heartbeat notes that nodeID still holds job's lock and is about to run
+// it. It goes through conn so a dead connection, and with it the lock, is
+// noticed, and fails when the lease has passed to another node.
+func (r *WorkerLeaseRepository) Heartbeat(ctx context.Context, conn *sql.Conn, job, nodeID string) error {
+	query := `UPDATE worker_leases SET heartbeat_at = NOW() WHERE job = $1 AND node_id = $2`
+	res, err := conn.ExecContext(ctx, query, job, nodeID)
+	if err != nil {
+		return fmt.Errorf("failed to renew worker lease: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to renew worker lease: %w", err)
+	}
+	if n != 1 {
+		return fmt.Errorf("worker lease is held by another node")
+	}
+	return nil
+}
+
+// List returns every job's lease, with whether its lock is still held by
+// any session.
+func (r *WorkerLeaseRepository) List(ctx context.Context) ([]dto.ResponseWorkerLease, error) {
+	query := `
+		SELECT w.job, w.node_id, w.acquired_at, w.heartbeat_at,
+			EXISTS (
+				SELECT 1 FROM pg_locks l
+				WHERE l.locktype = 'advisory' AND l.granted AND l.objsubid = 1
+					AND ((l.classid::bigint << 32) | l.objid::bigint) = w.lock_key
+			)
+		FROM worker_leases w
+		ORDER BY w.job
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker leases: %w", err)
+	}
+	defer rows.Close()
+
+	leases := []dto.ResponseWorkerLease{}
+	for rows.Next() {
+		var lease dto.ResponseWorkerLease
+		var acquiredAt, heartbeatAt time.Time
+		if err := rows.Scan(&lease.Job, &lease.NodeID, &acquiredAt, &heartbeatAt, &lease.Active); err != nil {
+			return nil, fmt.Errorf("failed to scan worker lease row: %w", err)
+		}
+		lease.AcquiredAt = acquiredAt.Format(time.RFC3339)
+		lease.HeartbeatAt = heartbeatAt.Format(time.RFC3339)
+		leases = append(leases, lease)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed during rows iteration: %w", err)
+	}
+	return leases, nil
+}
@@ -13,9 +13,10 @@ func SetupAuctionRoutes(
 	handler *handlers.Handler,
 	hub *websocket.Hub,
 	auctionRepo *repositories.AuctionRepository,
-	bidRepo *repositories.BidRepository,
 ) {
 	router.HandleFuncWithAuth(apiPath("/auctions"), handler.AuctionHandler, cfg)
 	router.HandleFuncWithAuth(apiPath("/auctions/{id}"), handler.AuctionByIDHandler, cfg)
-	router.HandleFunc(apiPath("/auctions/{id}/ws"), websocket.HandleAuctionWS(hub, cfg, auctionRepo, bidRepo))
+	router.HandleFuncWithAuth("POST "+apiPath("/auctions/{id}/buy-now"), handler.BuyNow, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/auctions/{id}/result"), handler.GetAuctionResult, cfg)
+	router.HandleFunc(apiPath("/auctions/{id}/ws"), websocket.HandleAuctionWS(hub, cfg, auctionRepo))
 }
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"rebid/internal/config"
+	"rebid/internal/handlers"
+	"rebid/internal/repositories"
+	"rebid/internal/websocket"
+)
+
+func SetupAuctionEventRoutes(
+	router Router,
+	cfg *config.Config,
+	handler *handlers.Handler,
+	hub *websocket.Hub,
+	eventRepo *repositories.AuctionEventRepository,
+	auctionRepo *repositories.AuctionRepository,
+) {
+	router.HandleFuncWithAuth("GET "+apiPath("/events"), handler.GetAllAuctionEvents, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/events"), handler.CreateAuctionEvent, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/events/{id}"), handler.GetAuctionEventByID, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/events/{id}/lots"), handler.GetAuctionEventLots, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/events/{id}/lots"), handler.CreateLot, cfg)
+	router.HandleFunc(apiPath("/events/{id}/ws"), websocket.HandleEventWS(hub, cfg, eventRepo, auctionRepo))
+}
@@ -7,4 +7,6 @@ import (
 
 func SetupBidRoutes(router Router, cfg *config.Config, handler *handlers.Handler) {
 	router.HandleFuncWithAuth(apiPath("/bids"), handler.BidHandler, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/bids/{id}/retract"), handler.RetractBid, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/bids/{id}/cancel"), handler.CancelBid, cfg)
 }
@@ -0,0 +1,10 @@
+package routes
+
+import (
+	"rebid/internal/config"
+	"rebid/internal/handlers"
+)
+
+func SetupHubRoutes(router Router, cfg *config.Config, handler *handlers.Handler) {
+	router.HandleFuncWithAuth("GET "+apiPath("/ws/metrics"), handler.GetHubMetrics, cfg)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"rebid/internal/config"
+	"rebid/internal/handlers"
+	"rebid/internal/repositories"
+	"rebid/internal/websocket"
+)
+
+func SetupNotificationRoutes(
+	router Router,
+	cfg *config.Config,
+	handler *handlers.Handler,
+	hub *websocket.Hub,
+	notifyRepo *repositories.NotificationRepository,
+) {
+	router.HandleFuncWithAuth("GET "+apiPath("/notifications"), handler.GetNotifications, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/notifications/unread-count"), handler.GetUnreadNotificationCount, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/notifications/read-all"), handler.MarkAllNotificationsRead, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/notifications/{id}/read"), handler.MarkNotificationRead, cfg)
+	router.HandleFunc(apiPath("/users/me/ws"), websocket.HandleUserWS(hub, cfg, notifyRepo))
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"rebid/internal/config"
+	"rebid/internal/handlers"
+)
+
+func SetupPaymentRoutes(router Router, cfg *config.Config, handler *handlers.Handler) {
+	router.HandleFuncWithAuth("POST "+apiPath("/auctions/{id}/checkout"), handler.Checkout, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/results/{id}/payments"), handler.GetPaymentHistory, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/results/{id}/refund"), handler.RefundPayment, cfg)
+	// The provider calls the webhook directly; it is authenticated by its
+	// signature rather than a user token.
+	router.HandleFunc("POST "+apiPath("/payments/webhook"), handler.PaymentWebhook)
+}
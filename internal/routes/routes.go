@@ -50,7 +50,7 @@ func apiPath(path string) string {
 func SetupRoutes(cfg *config.Config, deps *bootstrap.Dependencies) Router {
 	router := NewRouter(cfg)
 
-	handler := handlers.NewHandler(cfg, deps.Hub, deps.UserService, deps.ItemService, deps.AuctionService, deps.BidService)
+	handler := handlers.NewHandler(cfg, deps.UserService, deps.ItemService, deps.AuctionService, deps.BidService, deps.EventService, deps.WalletService, deps.PaymentService, deps.OfferService, deps.WatchService, deps.NotifyService, deps.WorkerService, deps.HubService)
 
 	router.HandleFunc("/health", handler.HealthCheck)
 	router.HandleFunc("/uploads/", func(w http.ResponseWriter, r *http.Request) {
@@ -59,7 +59,15 @@ func SetupRoutes(cfg *config.Config, deps *bootstrap.Dependencies) Router {
 
 	SetupUserRoutes(router, cfg, handler)
 	SetupItemRoutes(router, cfg, handler)
-	SetupAuctionRoutes(router, cfg, handler, deps.Hub, deps.AuctionRepo, deps.BidRepo)
+	SetupAuctionRoutes(router, cfg, handler, deps.Hub, deps.AuctionRepo)
 	SetupBidRoutes(router, cfg, handler)
+	SetupAuctionEventRoutes(router, cfg, handler, deps.Hub, deps.EventRepo, deps.AuctionRepo)
+	SetupWalletRoutes(router, cfg, handler)
+	SetupPaymentRoutes(router, cfg, handler)
+	SetupSecondChanceRoutes(router, cfg, handler)
+	SetupWatchlistRoutes(router, cfg, handler)
+	SetupNotificationRoutes(router, cfg, handler, deps.Hub, deps.NotifyRepo)
+	SetupWorkerRoutes(router, cfg, handler)
+	SetupHubRoutes(router, cfg, handler)
 	return router
 }
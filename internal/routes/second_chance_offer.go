@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"rebid/internal/config"
+	"rebid/internal/handlers"
+)
+
+func SetupSecondChanceRoutes(router Router, cfg *config.Config, handler *handlers.Handler) {
+	router.HandleFuncWithAuth("POST "+apiPath("/auctions/{id}/second-chance"), handler.CreateSecondChanceOffer, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/auctions/{id}/second-chance"), handler.GetAuctionSecondChanceOffers, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/second-chance-offers"), handler.GetMySecondChanceOffers, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/second-chance-offers/{id}/accept"), handler.AcceptSecondChanceOffer, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/second-chance-offers/{id}/decline"), handler.DeclineSecondChanceOffer, cfg)
+}
@@ -9,6 +9,8 @@ func SetupUserRoutes(router Router, cfg *config.Config, handler *handlers.Handle
 	router.HandleFunc(apiPath("/users/register"), handler.RegisterUser)
 	router.HandleFunc(apiPath("/users/login"), handler.LoginUser)
 	router.HandleFuncWithAuth(apiPath("/users/me"), handler.GetCurrentUser, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/users/me/auctions/won"), handler.GetWonAuctions, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/users/me/auctions/sold"), handler.GetSoldAuctions, cfg)
 	router.HandleFuncWithAuth(apiPath("/users/logout"), handler.LogoutUser, cfg)
 	router.HandleFunc(apiPath("/auth/google"), handler.GoogleAuthRedirect)
 	router.HandleFunc(apiPath("/auth/google/callback"), handler.GoogleAuthCallback)
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"rebid/internal/config"
+	"rebid/internal/handlers"
+)
+
+func SetupWalletRoutes(router Router, cfg *config.Config, handler *handlers.Handler) {
+	router.HandleFuncWithAuth("GET "+apiPath("/wallet"), handler.GetWallet, cfg)
+	router.HandleFuncWithAuth("GET "+apiPath("/wallet/entries"), handler.GetWalletEntries, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/wallet/credits"), handler.GrantCredit, cfg)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"rebid/internal/config"
+	"rebid/internal/handlers"
+)
+
+func SetupWatchlistRoutes(router Router, cfg *config.Config, handler *handlers.Handler) {
+	router.HandleFuncWithAuth("GET "+apiPath("/watchlist"), handler.GetWatchlist, cfg)
+	router.HandleFuncWithAuth("POST "+apiPath("/watchlist"), handler.WatchAuction, cfg)
+	router.HandleFuncWithAuth("DELETE "+apiPath("/watchlist/{id}"), handler.UnwatchAuction, cfg)
+}
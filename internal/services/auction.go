@@ -2,26 +2,55 @@ package services
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 	"rebid/internal/config"
 	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/notify"
 	"rebid/internal/repositories"
+	"rebid/internal/websocket"
 	"rebid/pkg"
-	"strings"
+	"reflect"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type AuctionService struct {
-	config *config.Config
-	repo   *repositories.AuctionRepository
+	config        *config.Config
+	db            *sql.DB
+	repo          *repositories.AuctionRepository
+	bidRepo       *repositories.BidRepository
+	proxyRepo     *repositories.ProxyBidRepository
+	resultRepo    *repositories.AuctionResultRepository
+	wallet        *WalletService
+	notifications *NotificationService
+	events        *DomainEventService
 }
 
-func NewAuctionService(cfg *config.Config, auctionRepo *repositories.AuctionRepository) *AuctionService {
+func NewAuctionService(
+	cfg *config.Config,
+	db *sql.DB,
+	auctionRepo *repositories.AuctionRepository,
+	bidRepo *repositories.BidRepository,
+	proxyRepo *repositories.ProxyBidRepository,
+	resultRepo *repositories.AuctionResultRepository,
+	wallet *WalletService,
+	notifications *NotificationService,
+	events *DomainEventService,
+) *AuctionService {
 	return &AuctionService{
-		config: cfg,
-		repo:   auctionRepo,
+		config:        cfg,
+		db:            db,
+		repo:          auctionRepo,
+		bidRepo:       bidRepo,
+		proxyRepo:     proxyRepo,
+		resultRepo:    resultRepo,
+		wallet:        wallet,
+		notifications: notifications,
+		events:        events,
 	}
 }
 
@@ -33,32 +62,319 @@ func (s *AuctionService) CreateAuction(ctx context.Context, auction *dto.CreateA
 	return s.repo.Create(ctx, auction, userID)
 }
 
-func (s *AuctionService) UpdateAuction(ctx context.Context, auction *dto.UpdateAuctionRequest, auctionID string) (*dto.ResponseAuction, error) {
+// UpdateAuction applies an owner or admin edit. A status change must be a
+// legal transition for that actor; it is audited and its effects are applied
+// in the same transaction as the edit.
+func (s *AuctionService) UpdateAuction(ctx context.Context, auction *dto.UpdateAuctionRequest, auctionID string, userID uuid.UUID, role string) (*dto.ResponseAuction, error) {
 	auctionUUID, err := uuid.Parse(auctionID)
 	if err != nil {
 		return nil, pkg.NewError("invalid auction ID format", http.StatusBadRequest)
 	}
 
-	return s.repo.Update(ctx, auction, auctionUUID)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	state, err := s.repo.LockForTransition(ctx, tx, auctionUUID)
+	if err != nil {
+		if err.Error() == "auction not found" {
+			return nil, pkg.NewError("auction not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	var actor models.AuctionActor
+	switch {
+	case role == string(models.RoleAdmin):
+		actor = models.ActorAdmin
+	case state.CreatedBy == userID:
+		actor = models.ActorOwner
+	default:
+		return nil, pkg.NewError("forbidden: you don't own this auction", http.StatusForbidden)
+	}
+
+	if err := checkAuctionTypeUpdate(state.AuctionType, state.Quantity, auction); err != nil {
+		return nil, err
+	}
+	if err := checkAuctionTermsUpdate(state, auction); err != nil {
+		return nil, err
+	}
+
+	var transition *AuctionTransition
+	if auction.Status != nil && models.AuctionStatus(*auction.Status) != state.Status {
+		transition, err = checkAuctionTransition(state.Status, models.AuctionStatus(*auction.Status), actor, state.HasBids)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := s.repo.Update(ctx, tx, auction, auctionUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if transition != nil {
+		if err := s.applyTransition(ctx, tx, auctionUUID, transition, &userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	if transition != nil && transition.Effects.DecideOutcome {
+		// The outcome is written after the edit, so re-read it.
+		response, err = s.GetAuctionByID(ctx, auctionID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
 }
 
-func (s *AuctionService) GetAuctionByID(ctx context.Context, auctionID string) (*dto.ResponseAuction, error) {
+// BuyNow sells the auction to userID at its buy-now price. The winning bid,
+// the ENDED status and the outcome are written in one transaction.
+func (s *AuctionService) BuyNow(ctx context.Context, auctionID string, userID uuid.UUID) (*dto.ResponseBid, error) {
 	auctionUUID, err := uuid.Parse(auctionID)
 	if err != nil {
 		return nil, pkg.NewError("invalid auction ID format", http.StatusBadRequest)
 	}
 
-	auction, err := s.repo.GetByID(ctx, auctionUUID)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	eligibility, err := s.repo.LockAuctionForBid(ctx, tx, auctionUUID)
+	if err != nil {
+		if err.Error() == "auction not found" {
+			return nil, pkg.NewError("auction not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	if eligibility.CreatedBy == userID {
+		return nil, pkg.NewError("forbidden: you can't buy your own auction", http.StatusForbidden)
+	}
+	if eligibility.Status != string(models.AuctionActive) || time.Now().UTC().After(eligibility.EndTime.UTC()) {
+		return nil, pkg.NewError("auction is not active", http.StatusConflict)
+	}
+	if eligibility.BuyNowPrice == nil || eligibility.CurrentPrice >= *eligibility.BuyNowPrice {
+		return nil, pkg.NewError("buy now is not available for this auction", http.StatusConflict)
+	}
+
+	bid, err := s.sellTo(ctx, tx, auctionUUID, *eligibility.BuyNowPrice, userID, eligibility.CurrentBidderID != nil)
 	if err != nil {
 		return nil, err
 	}
-	if auction.Item != nil {
-		baseURL := strings.TrimSuffix(s.config.BaseURL, "/")
-		for i := range auction.Item.Images {
-			path := strings.TrimPrefix(auction.Item.Images[i].URL, "/")
-			auction.Item.Images[i].URL = fmt.Sprintf("%s/%s", baseURL, path)
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return bid, nil
+}
+
+// sellTo ends a locked ACTIVE auction immediately with buyer as the winner
+// at price: it records the winning bid, writes the ENDED status and applies
+// the transition as the system, with the buyer recorded as the actor.
+func (s *AuctionService) sellTo(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, price float64, buyer uuid.UUID, hasBids bool) (*dto.ResponseBid, error) {
+	transition, err := checkAuctionTransition(models.AuctionActive, models.AuctionEnded, models.ActorSystem, hasBids)
+	if err != nil {
+		return nil, err
+	}
+
+	bid, err := s.bidRepo.Create(ctx, tx, &dto.CreateBidRequest{AuctionID: auctionID, Amount: price}, buyer)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateCurrentPriceWithBidder(ctx, tx, auctionID, price, buyer); err != nil {
+		return nil, fmt.Errorf("failed to update auction: %w", err)
+	}
+	if err := s.repo.EndAuction(ctx, tx, auctionID); err != nil {
+		return nil, fmt.Errorf("failed to end auction: %w", err)
+	}
+	if err := s.applyTransition(ctx, tx, auctionID, transition, &buyer); err != nil {
+		return nil, err
+	}
+	if err := s.wallet.syncHolds(ctx, tx, auctionID, &buyer); err != nil {
+		return nil, err
+	}
+	return bid, nil
+}
+
+// checkAuctionTypeUpdate rejects edits that do not apply to the auction's
+// type and quantity, which are fixed at creation.
+func checkAuctionTypeUpdate(auctionType models.AuctionType, quantity int, auction *dto.UpdateAuctionRequest) error {
+	if auctionType != models.AuctionDutch && auction.DutchSchedule != nil {
+		return pkg.NewError("dutch schedule is only allowed on dutch auctions", http.StatusBadRequest)
+	}
+
+	switch {
+	case auctionType == models.AuctionDutch:
+		if auction.ReservePrice != nil || auction.BuyNowPrice != nil || auction.SoftClose != nil {
+			return pkg.NewError("dutch auctions do not support reserve, buy now or soft close", http.StatusBadRequest)
 		}
+	case auctionType.IsSealed():
+		if auction.BuyNowPrice != nil || auction.SoftClose != nil {
+			return pkg.NewError("sealed-bid auctions do not support buy now or soft close", http.StatusBadRequest)
+		}
+	}
+	if quantity > 1 && auction.BuyNowPrice != nil {
+		return pkg.NewError("multi-quantity auctions do not support buy now", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// checkAuctionTermsUpdate freezes an auction's terms once someone has bid or
+// it has ended or been cancelled. Only its status may change after that, so
+// the edit has to repeat the current terms.
+func checkAuctionTermsUpdate(state *repositories.AuctionTransitionState, auction *dto.UpdateAuctionRequest) error {
+	if !state.HasBids && state.Status != models.AuctionEnded && state.Status != models.AuctionCancelled {
+		return nil
 	}
+
+	changed := auction.StartingPrice != state.StartingPrice ||
+		!auction.StartTime.Equal(state.StartTime) ||
+		!auction.EndTime.Equal(state.EndTime) ||
+		!reflect.DeepEqual(auction.ReservePrice, state.ReservePrice) ||
+		!reflect.DeepEqual(auction.BuyNowPrice, state.BuyNowPrice) ||
+		!reflect.DeepEqual(auction.SoftClose, state.SoftClose) ||
+		(auction.BidIncrement != nil && !reflect.DeepEqual(*auction.BidIncrement, state.BidIncrement)) ||
+		(auction.DutchSchedule != nil && !reflect.DeepEqual(auction.DutchSchedule, state.DutchSchedule))
+	if !changed {
+		return nil
+	}
+	if state.HasBids {
+		return pkg.NewError("auction already has bids, only its status can change", http.StatusConflict)
+	}
+	return pkg.NewError(fmt.Sprintf("auction is %s, its terms can no longer change", state.Status), http.StatusConflict)
+}
+
+// applyTransition records the audit row and runs the effects of a transition
+// that has already been checked. The caller writes the new status first.
+func (s *AuctionService) applyTransition(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, t *AuctionTransition, actorID *uuid.UUID) error {
+	if err := s.repo.RecordStatusTransition(ctx, tx, auctionID, t.From, t.To, t.Actor, actorID); err != nil {
+		return err
+	}
+	if t.Effects.ResolveSealedBids {
+		if err := s.repo.ResolveSealedBids(ctx, tx, auctionID); err != nil {
+			return err
+		}
+	}
+	if t.Effects.DecideOutcome {
+		if err := s.repo.DecideOutcome(ctx, tx, auctionID); err != nil {
+			return err
+		}
+	}
+	if t.Effects.Settle {
+		if err := s.resultRepo.Create(ctx, tx, auctionID, s.config.BuyerPremiumPercent, s.config.SellerFeePercent); err != nil {
+			return err
+		}
+	}
+	if t.Effects.ReleaseHolds {
+		if err := s.wallet.syncHolds(ctx, tx, auctionID, nil); err != nil {
+			return err
+		}
+	}
+	if t.Effects.FreezeBids {
+		if err := s.proxyRepo.DeleteByAuctionID(ctx, tx, auctionID); err != nil {
+			return err
+		}
+	}
+	if t.Effects.Change != "" {
+		if err := s.events.record(ctx, tx, auctionID, t.Effects.Change); err != nil {
+			return err
+		}
+	}
+	if t.Effects.Notify {
+		if err := s.notifyTransition(ctx, tx, auctionID, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyTransition queues the notifications users get when an auction
+// starts or ends: its watchers hear that it started, and each winner that
+// they won.
+func (s *AuctionService) notifyTransition(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, t *AuctionTransition) error {
+	switch t.To {
+	case models.AuctionActive:
+		return s.notifications.enqueueForWatchers(ctx, tx, auctionID, notify.KindAuctionStarted,
+			"An auction you watch has started", "Bidding is now open on an auction on your watchlist.")
+	case models.AuctionEnded:
+		winners, err := s.resultRepo.ListWinners(ctx, tx, auctionID)
+		if err != nil {
+			return err
+		}
+		for _, result := range winners {
+			err := s.notifications.enqueue(ctx, tx, notify.Message{
+				UserID:    *result.WinnerID,
+				Kind:      notify.KindAuctionWon,
+				Subject:   "You won an auction",
+				Body:      fmt.Sprintf("You won %d unit(s) at %.2f each. Your total is %.2f.", result.Quantity, *result.HammerPrice, result.BuyerTotal),
+				AuctionID: &auctionID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// systemTransition moves every auction selected by move from one status to
+// another on behalf of the worker, auditing each of them.
+func (s *AuctionService) systemTransition(
+	ctx context.Context,
+	from, to models.AuctionStatus,
+	move func(context.Context, *sql.Tx) ([]uuid.UUID, error),
+) ([]uuid.UUID, error) {
+	transition, err := checkAuctionTransition(from, to, models.ActorSystem, false)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := move(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if err := s.applyTransition(ctx, tx, id, transition, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return ids, nil
+}
+
+func (s *AuctionService) GetAuctionByID(ctx context.Context, auctionID string) (*dto.ResponseAuction, error) {
+	auctionUUID, err := uuid.Parse(auctionID)
+	if err != nil {
+		return nil, pkg.NewError("invalid auction ID format", http.StatusBadRequest)
+	}
+
+	auction, err := s.repo.GetByID(ctx, auctionUUID)
+	if err != nil {
+		return nil, err
+	}
+	auction.ResolveImageURLs(s.config.BaseURL)
 	return auction, nil
 }
 
@@ -72,5 +388,55 @@ func (s *AuctionService) DeleteAuction(ctx context.Context, auctionID string) er
 }
 
 func (s *AuctionService) CloseExpiredAuctions(ctx context.Context) ([]uuid.UUID, error) {
-	return s.repo.CloseExpiredAuctions(ctx)
+	return s.systemTransition(ctx, models.AuctionActive, models.AuctionEnded, s.repo.CloseExpiredAuctions)
+}
+
+// DropDutchPrices applies every due price drop on running Dutch auctions and
+// returns the auctions whose price changed.
+func (s *AuctionService) DropDutchPrices(ctx context.Context) ([]uuid.UUID, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := s.repo.DropDutchPrices(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.events.recordAll(ctx, tx, ids, websocket.ChangePriceTick); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return ids, nil
+}
+
+// StaggerEventLots pushes back event lots that would otherwise close out of
+// sequence and returns the lots that moved.
+func (s *AuctionService) StaggerEventLots(ctx context.Context) ([]uuid.UUID, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids, err := s.repo.StaggerEventLots(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.events.recordAll(ctx, tx, ids, websocket.ChangeAuctionExtended); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return ids, nil
+}
+
+func (s *AuctionService) ActivateScheduledAuctions(ctx context.Context) ([]uuid.UUID, error) {
+	return s.systemTransition(ctx, models.AuctionScheduled, models.AuctionActive, s.repo.ActivateScheduledAuctions)
 }
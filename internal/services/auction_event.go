@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/repositories"
+	"rebid/pkg"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuctionEventService struct {
+	config      *config.Config
+	repo        *repositories.AuctionEventRepository
+	auctionRepo *repositories.AuctionRepository
+}
+
+func NewAuctionEventService(cfg *config.Config, eventRepo *repositories.AuctionEventRepository, auctionRepo *repositories.AuctionRepository) *AuctionEventService {
+	return &AuctionEventService{
+		config:      cfg,
+		repo:        eventRepo,
+		auctionRepo: auctionRepo,
+	}
+}
+
+func (s *AuctionEventService) CreateEvent(ctx context.Context, event *dto.CreateAuctionEventRequest, userID uuid.UUID) (*dto.ResponseAuctionEvent, error) {
+	created, err := s.repo.Create(ctx, event, userID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewResponseAuctionEvent(created, "SCHEDULED", []dto.ResponseAuction{}), nil
+}
+
+func (s *AuctionEventService) GetAllEvents(ctx context.Context, filter *dto.FilterAuctionEvent) ([]dto.ResponseAuctionEvent, error) {
+	return s.repo.GetAll(ctx, filter)
+}
+
+// GetEventByID returns an event with all of its lots in lot order.
+func (s *AuctionEventService) GetEventByID(ctx context.Context, eventID string) (*dto.ResponseAuctionEvent, error) {
+	eventUUID, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, pkg.NewError("invalid event ID format", http.StatusBadRequest)
+	}
+
+	event, err := s.repo.GetByID(ctx, eventUUID)
+	if err != nil {
+		if err.Error() == "auction event not found" {
+			return nil, pkg.NewError("auction event not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	status, err := s.repo.GetStatus(ctx, eventUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	lots, err := s.auctionRepo.GetAll(ctx, &dto.FilterAuction{EventID: &eventUUID})
+	if err != nil {
+		return nil, err
+	}
+	return dto.NewResponseAuctionEvent(event, status, lots), nil
+}
+
+// GetEventLots lists an event's lots, narrowed by the usual auction filters.
+func (s *AuctionEventService) GetEventLots(ctx context.Context, eventID string, filter *dto.FilterAuction) ([]dto.ResponseAuction, error) {
+	eventUUID, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, pkg.NewError("invalid event ID format", http.StatusBadRequest)
+	}
+
+	filter.EventID = &eventUUID
+	return s.auctionRepo.GetAll(ctx, filter)
+}
+
+// AddLot creates an auction as the next lot of an event. Lots open with the
+// event and close in lot order, so they can only be added before it starts.
+func (s *AuctionEventService) AddLot(ctx context.Context, eventID string, lot *dto.CreateLotRequest, userID uuid.UUID) (*dto.ResponseAuction, error) {
+	eventUUID, err := uuid.Parse(eventID)
+	if err != nil {
+		return nil, pkg.NewError("invalid event ID format", http.StatusBadRequest)
+	}
+
+	event, err := s.repo.GetByID(ctx, eventUUID)
+	if err != nil {
+		if err.Error() == "auction event not found" {
+			return nil, pkg.NewError("auction event not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	if event.CreatedBy != userID {
+		return nil, pkg.NewError("forbidden: you don't own this event", http.StatusForbidden)
+	}
+	if !time.Now().UTC().Before(event.StartTime.UTC()) {
+		return nil, pkg.NewError("lots can only be added before the event starts", http.StatusConflict)
+	}
+
+	lotNumber, err := s.repo.NextLotNumber(ctx, eventUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	auction := lot.ToAuctionRequest(event, lotNumber)
+	if err := auction.Validate(); err != nil {
+		return nil, pkg.NewError(err.Error(), http.StatusBadRequest)
+	}
+
+	created, err := s.auctionRepo.Create(ctx, auction, userID)
+	if err != nil {
+		switch err.Error() {
+		case "lot number already taken", "auction for this item already exists":
+			return nil, pkg.NewError(err.Error(), http.StatusConflict)
+		}
+		return nil, err
+	}
+	return created, nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/pkg"
+
+	"github.com/google/uuid"
+)
+
+// GetAuctionResult returns the settlements of an ended auction. The seller
+// and admins see all of them; a winner sees only their own.
+func (s *AuctionService) GetAuctionResult(ctx context.Context, auctionID string, userID uuid.UUID, role string) ([]dto.ResponseAuctionResult, error) {
+	auctionUUID, err := uuid.Parse(auctionID)
+	if err != nil {
+		return nil, pkg.NewError("invalid auction ID format", http.StatusBadRequest)
+	}
+
+	results, err := s.resultRepo.ListByAuctionID(ctx, auctionUUID)
+	if err != nil {
+		if err.Error() == "auction result not found" {
+			return nil, pkg.NewError("auction result not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	if role == string(models.RoleAdmin) || results[0].SellerID == userID {
+		return results, nil
+	}
+
+	var own []dto.ResponseAuctionResult
+	for _, result := range results {
+		if result.WinnerID != nil && *result.WinnerID == userID {
+			own = append(own, result)
+		}
+	}
+	if len(own) == 0 {
+		return nil, pkg.NewError("forbidden: you are not a party to this auction", http.StatusForbidden)
+	}
+	return own, nil
+}
+
+func (s *AuctionService) GetWonAuctions(ctx context.Context, userID uuid.UUID) ([]dto.ResponseAuctionResult, error) {
+	return s.resultRepo.ListWonByUser(ctx, userID)
+}
+
+func (s *AuctionService) GetSoldAuctions(ctx context.Context, userID uuid.UUID) ([]dto.ResponseAuctionResult, error) {
+	return s.resultRepo.ListSoldByUser(ctx, userID)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"rebid/internal/models"
+	"rebid/internal/websocket"
+	"rebid/pkg"
+	"strings"
+)
+
+// TransitionEffects are the side effects of entering a status.
+type TransitionEffects struct {
+	// FreezeBids drops the stored proxy ceilings so nothing bids automatically
+	// once the auction is no longer ACTIVE.
+	FreezeBids bool
+	// ResolveSealedBids opens a sealed-bid auction's bids and sets the winner
+	// and clearing price before the outcome is decided.
+	ResolveSealedBids bool
+	// DecideOutcome settles whether the current bidder won, honoring the
+	// reserve price.
+	DecideOutcome bool
+	// Settle writes the auction result from the decided outcome.
+	Settle bool
+	// ReleaseHolds returns bidders' held credit on a deposit auction, keeping
+	// only what the winners owe.
+	ReleaseHolds bool
+	// Notify tells the seller and bidders about the change.
+	Notify bool
+	// Change is the domain event recorded for the transition, which auction
+	// subscribers receive as the websocket change.
+	Change string
+}
+
+// AuctionTransition is a status change that passed the state machine.
+type AuctionTransition struct {
+	From    models.AuctionStatus
+	To      models.AuctionStatus
+	Actor   models.AuctionActor
+	Effects TransitionEffects
+}
+
+type transitionRule struct {
+	actors []models.AuctionActor
+	// ownerNeedsNoBids limits the owner to auctions nobody has bid on yet.
+	ownerNeedsNoBids bool
+	effects          TransitionEffects
+}
+
+// auctionTransitions lists every legal status change. ENDED and CANCELLED
+// are terminal.
+var auctionTransitions = map[models.AuctionStatus]map[models.AuctionStatus]transitionRule{
+	models.AuctionScheduled: {
+		models.AuctionActive: {
+			actors:  []models.AuctionActor{models.ActorOwner, models.ActorAdmin, models.ActorSystem},
+			effects: TransitionEffects{Notify: true, Change: websocket.ChangeAuctionStarted},
+		},
+		models.AuctionCancelled: {
+			actors:  []models.AuctionActor{models.ActorOwner, models.ActorAdmin},
+			effects: TransitionEffects{FreezeBids: true, Notify: true, Change: websocket.ChangeAuctionCancelled},
+		},
+	},
+	models.AuctionActive: {
+		models.AuctionEnded: {
+			actors:  []models.AuctionActor{models.ActorAdmin, models.ActorSystem},
+			effects: TransitionEffects{FreezeBids: true, ResolveSealedBids: true, DecideOutcome: true, Settle: true, ReleaseHolds: true, Notify: true, Change: websocket.ChangeAuctionEnded},
+		},
+		models.AuctionCancelled: {
+			actors:           []models.AuctionActor{models.ActorOwner, models.ActorAdmin},
+			ownerNeedsNoBids: true,
+			effects:          TransitionEffects{FreezeBids: true, ReleaseHolds: true, Notify: true, Change: websocket.ChangeAuctionCancelled},
+		},
+	},
+}
+
+func checkAuctionTransition(from, to models.AuctionStatus, actor models.AuctionActor, hasBids bool) (*AuctionTransition, error) {
+	rule, ok := auctionTransitions[from][to]
+	if !ok {
+		return nil, pkg.NewError(fmt.Sprintf("cannot change auction status from %s to %s", from, to), http.StatusConflict)
+	}
+
+	allowed := false
+	for _, a := range rule.actors {
+		if a == actor {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		names := make([]string, len(rule.actors))
+		for i, a := range rule.actors {
+			names[i] = strings.ToLower(string(a))
+		}
+		return nil, pkg.NewError(fmt.Sprintf("only %s may change auction status from %s to %s", strings.Join(names, ", "), from, to), http.StatusForbidden)
+	}
+
+	if actor == models.ActorOwner && rule.ownerNeedsNoBids && hasBids {
+		return nil, pkg.NewError(fmt.Sprintf("auction already has bids, only an admin may change its status to %s", to), http.StatusConflict)
+	}
+
+	return &AuctionTransition{From: from, To: to, Actor: actor, Effects: rule.effects}, nil
+}
@@ -4,46 +4,62 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"net/http"
 	"rebid/internal/config"
 	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/notify"
 	"rebid/internal/repositories"
+	"rebid/internal/websocket"
+	"rebid/pkg"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type BidService struct {
-	db          *sql.DB
-	repo        *repositories.BidRepository
-	auctionRepo *repositories.AuctionRepository
-	config      *config.Config
+	db            *sql.DB
+	repo          *repositories.BidRepository
+	proxyRepo     *repositories.ProxyBidRepository
+	auctionRepo   *repositories.AuctionRepository
+	auctions      *AuctionService
+	wallet        *WalletService
+	notifications *NotificationService
+	events        *DomainEventService
+	config        *config.Config
 }
 
-func NewBidService(cfg *config.Config, db *sql.DB, repo *repositories.BidRepository, auctionRepo *repositories.AuctionRepository) *BidService {
+func NewBidService(cfg *config.Config, db *sql.DB, repo *repositories.BidRepository, proxyRepo *repositories.ProxyBidRepository, auctionRepo *repositories.AuctionRepository, auctions *AuctionService, wallet *WalletService, notifications *NotificationService, events *DomainEventService) *BidService {
 	return &BidService{
-		db:          db,
-		repo:        repo,
-		auctionRepo: auctionRepo,
-		config:      cfg,
+		db:            db,
+		repo:          repo,
+		proxyRepo:     proxyRepo,
+		auctionRepo:   auctionRepo,
+		auctions:      auctions,
+		wallet:        wallet,
+		notifications: notifications,
+		events:        events,
+		config:        cfg,
 	}
 }
 
-func (s *BidService) CreateBid(ctx context.Context, bid *dto.CreateBidRequest, userID uuid.UUID) (*dto.ResponseBid, error) {
-	eligibility, err := s.auctionRepo.GetAuctionForBid(ctx, bid.AuctionID)
+func (s *BidService) CreateBid(ctx context.Context, bid *dto.CreateBidRequest, userID uuid.UUID) (*dto.ResponseCreateBid, error) {
+	snapshot, err := s.auctionRepo.GetAuctionForBid(ctx, bid.AuctionID)
 	if err != nil {
 		return nil, err
 	}
 
-	if eligibility.Status != "ACTIVE" {
-		return nil, fmt.Errorf("auction is not active")
+	if err := checkBidEligibility(snapshot, bid.Amount); err != nil {
+		return nil, err
 	}
-
-	if time.Now().UTC().After(eligibility.EndTime.UTC()) {
-		return nil, fmt.Errorf("auction has already ended")
+	if snapshot.AuctionType != models.AuctionEnglish && bid.MaxAmount != nil {
+		return nil, pkg.NewError("max amount is only supported on english auctions", http.StatusBadRequest)
 	}
-
-	if eligibility.CurrentPrice >= bid.Amount {
-		return nil, fmt.Errorf("bid amount must be greater than current price")
+	if bid.Quantity > snapshot.Quantity {
+		return nil, pkg.NewError(fmt.Sprintf("quantity can't exceed the %d units on offer", snapshot.Quantity), http.StatusBadRequest)
+	}
+	if snapshot.Quantity > 1 && bid.MaxAmount != nil {
+		return nil, pkg.NewError("max amount is not supported on multi-quantity auctions", http.StatusBadRequest)
 	}
 
 	tx, err := s.db.BeginTx(ctx, nil)
@@ -52,21 +68,475 @@ func (s *BidService) CreateBid(ctx context.Context, bid *dto.CreateBidRequest, u
 	}
 	defer tx.Rollback()
 
+	// Re-check under the row lock: another bid may have committed since the
+	// snapshot above, and only the locked row is safe to build on.
+	eligibility, err := s.auctionRepo.LockAuctionForBid(ctx, tx, bid.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkBidEligibility(eligibility, bid.Amount); err != nil {
+		if eligibility.CurrentPrice != snapshot.CurrentPrice {
+			return nil, newOutbidError(eligibility)
+		}
+		return nil, err
+	}
+
+	if eligibility.DepositRequired {
+		if err := s.wallet.checkCredit(ctx, tx, bid.AuctionID, userID, bidCommitment(eligibility, bid)); err != nil {
+			return nil, err
+		}
+	}
+
+	if eligibility.AuctionType == models.AuctionDutch {
+		return s.acceptDutchPrice(ctx, tx, eligibility, bid.AuctionID, userID)
+	}
+	if eligibility.AuctionType.IsSealed() {
+		return s.placeSealedBid(ctx, tx, eligibility, bid, userID)
+	}
+	if eligibility.Quantity > 1 {
+		return s.placeUnitBid(ctx, tx, eligibility, bid, userID)
+	}
+
 	createdBid, err := s.repo.Create(ctx, tx, bid, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.auctionRepo.UpdateCurrentPriceWithBidder(ctx, tx, bid.AuctionID, bid.Amount, userID)
+	if bid.MaxAmount != nil {
+		if err := s.proxyRepo.Upsert(ctx, tx, bid.AuctionID, userID, *bid.MaxAmount); err != nil {
+			return nil, err
+		}
+	}
+
+	price, leader, autoBids, err := s.resolveProxyBids(ctx, tx, bid.AuctionID, eligibility.BidIncrement, bid.Amount, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve proxy bids: %w", err)
+	}
+
+	err = s.auctionRepo.UpdateCurrentPriceWithBidder(ctx, tx, bid.AuctionID, price, leader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update auction: %w", err)
 	}
 
+	if eligibility.BuyNowPrice != nil && price > *eligibility.BuyNowPrice*s.config.BuyNowDisableFraction {
+		if err := s.auctionRepo.ClearBuyNowPrice(ctx, tx, bid.AuctionID); err != nil {
+			return nil, fmt.Errorf("failed to withdraw buy now: %w", err)
+		}
+	}
+
+	if err := s.wallet.syncHolds(ctx, tx, bid.AuctionID, &userID); err != nil {
+		return nil, err
+	}
+
+	if prev := eligibility.CurrentBidderID; prev != nil && *prev != leader {
+		if err := s.notifyOutbid(ctx, tx, bid.AuctionID, price, []uuid.UUID{*prev}); err != nil {
+			return nil, err
+		}
+	}
+	bidIDs := append([]uuid.UUID{createdBid.ID}, autoBids...)
+	if err := s.events.recordBids(ctx, tx, bid.AuctionID, websocket.ChangeNewBid, bidIDs); err != nil {
+		return nil, err
+	}
+
+	result := &dto.ResponseCreateBid{
+		ResponseBid: *createdBid,
+		EndTime:     eligibility.EndTime,
+	}
+	if err := s.applySoftClose(ctx, tx, eligibility, bid.AuctionID, result); err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit: %w", err)
 	}
 
-	return createdBid, nil
+	return result, nil
+}
+
+// applySoftClose pushes the auction's end back when a bid lands inside its
+// soft-close window, noting the new end on result.
+func (s *BidService) applySoftClose(ctx context.Context, tx *sql.Tx, e *repositories.AuctionBidEligibility, auctionID uuid.UUID, result *dto.ResponseCreateBid) error {
+	if e.SoftClose == nil {
+		return nil
+	}
+	endTime, extended := e.SoftClose.Extend(time.Now().UTC(), e.EndTime.UTC(), e.OriginalEndTime.UTC())
+	if !extended {
+		return nil
+	}
+	if err := s.auctionRepo.ExtendEndTime(ctx, tx, auctionID, endTime); err != nil {
+		return fmt.Errorf("failed to extend auction: %w", err)
+	}
+	if err := s.events.record(ctx, tx, auctionID, websocket.ChangeAuctionExtended); err != nil {
+		return err
+	}
+	result.AuctionExtended = true
+	result.EndTime = endTime
+	return nil
+}
+
+// bidCommitment is the most a bid can end up costing its bidder, which a
+// deposit auction requires them to have credit for.
+func bidCommitment(e *repositories.AuctionBidEligibility, bid *dto.CreateBidRequest) float64 {
+	switch {
+	case e.AuctionType == models.AuctionDutch:
+		return e.CurrentPrice
+	case e.Quantity > 1:
+		return bid.Amount * float64(max(bid.Quantity, 1))
+	case bid.MaxAmount != nil:
+		return *bid.MaxAmount
+	default:
+		return bid.Amount
+	}
+}
+
+// placeUnitBid records a bid on a multi-quantity auction. A bidder's latest
+// bid replaces their earlier ones and may not ask for less, in amount or in
+// units. The auction is then repriced to the new clearing price.
+func (s *BidService) placeUnitBid(ctx context.Context, tx *sql.Tx, e *repositories.AuctionBidEligibility, bid *dto.CreateBidRequest, userID uuid.UUID) (*dto.ResponseCreateBid, error) {
+	if e.CreatedBy == userID {
+		return nil, pkg.NewError("forbidden: you can't bid on your own auction", http.StatusForbidden)
+	}
+
+	quantity := max(bid.Quantity, 1)
+	standing, err := s.repo.GetStandingBid(ctx, tx, bid.AuctionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if standing != nil && (bid.Amount < standing.Amount || quantity < standing.Quantity) {
+		return nil, pkg.NewError(fmt.Sprintf("your bid can't go below your standing bid of %d at %.2f", standing.Quantity, standing.Amount), http.StatusConflict)
+	}
+
+	before, err := s.repo.ListInTheMoney(ctx, tx, bid.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	createdBid, err := s.repo.Create(ctx, tx, bid, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	price, _, err := s.auctionRepo.RepriceUnits(ctx, tx, bid.AuctionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update auction: %w", err)
+	}
+	if err := s.wallet.syncHolds(ctx, tx, bid.AuctionID, &userID); err != nil {
+		return nil, err
+	}
+
+	after, err := s.repo.ListInTheMoney(ctx, tx, bid.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.notifyOutbid(ctx, tx, bid.AuctionID, price, droppedOut(before, after)); err != nil {
+		return nil, err
+	}
+	if err := s.events.recordBids(ctx, tx, bid.AuctionID, websocket.ChangeNewBid, []uuid.UUID{createdBid.ID}); err != nil {
+		return nil, err
+	}
+
+	result := &dto.ResponseCreateBid{
+		ResponseBid: *createdBid,
+		EndTime:     e.EndTime,
+	}
+	if err := s.applySoftClose(ctx, tx, e, bid.AuctionID, result); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return result, nil
+}
+
+// notifyOutbid queues an outbid notification for each of users in tx, so it
+// is sent only if the bid that outbid them commits.
+func (s *BidService) notifyOutbid(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, price float64, users []uuid.UUID) error {
+	for _, userID := range users {
+		err := s.notifications.enqueue(ctx, tx, notify.Message{
+			UserID:    userID,
+			Kind:      notify.KindOutbid,
+			Subject:   "You have been outbid",
+			Body:      fmt.Sprintf("Someone outbid you. The price is now %.2f.", price),
+			AuctionID: &auctionID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// droppedOut returns the users in before who are no longer in after.
+func droppedOut(before, after []uuid.UUID) []uuid.UUID {
+	still := make(map[uuid.UUID]bool, len(after))
+	for _, userID := range after {
+		still[userID] = true
+	}
+	var out []uuid.UUID
+	for _, userID := range before {
+		if !still[userID] {
+			out = append(out, userID)
+		}
+	}
+	return out
+}
+
+// acceptDutchPrice sells a locked Dutch auction to the first bidder willing
+// to pay its current price. The bid is recorded at that price even if the
+// bidder offered more.
+func (s *BidService) acceptDutchPrice(ctx context.Context, tx *sql.Tx, e *repositories.AuctionBidEligibility, auctionID, userID uuid.UUID) (*dto.ResponseCreateBid, error) {
+	if e.CreatedBy == userID {
+		return nil, pkg.NewError("forbidden: you can't bid on your own auction", http.StatusForbidden)
+	}
+
+	// The bid is reported before the sale it ends the auction with.
+	if err := s.events.record(ctx, tx, auctionID, websocket.ChangeNewBid); err != nil {
+		return nil, err
+	}
+	createdBid, err := s.auctions.sellTo(ctx, tx, auctionID, e.CurrentPrice, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return &dto.ResponseCreateBid{
+		ResponseBid:  *createdBid,
+		AuctionEnded: true,
+		EndTime:      time.Now().UTC(),
+	}, nil
+}
+
+// placeSealedBid records a bidder's single sealed bid. The auction's price
+// and leader are left alone so nothing about the bids leaks before close,
+// when the closer resolves the winner.
+func (s *BidService) placeSealedBid(ctx context.Context, tx *sql.Tx, e *repositories.AuctionBidEligibility, bid *dto.CreateBidRequest, userID uuid.UUID) (*dto.ResponseCreateBid, error) {
+	if e.CreatedBy == userID {
+		return nil, pkg.NewError("forbidden: you can't bid on your own auction", http.StatusForbidden)
+	}
+
+	exists, err := s.repo.HasUserBid(ctx, tx, bid.AuctionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, pkg.NewError("you have already placed a sealed bid on this auction", http.StatusConflict)
+	}
+
+	createdBid, err := s.repo.Create(ctx, tx, bid, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.wallet.syncHolds(ctx, tx, bid.AuctionID, &userID); err != nil {
+		return nil, err
+	}
+	if err := s.events.recordBids(ctx, tx, bid.AuctionID, websocket.ChangeNewBid, []uuid.UUID{createdBid.ID}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return &dto.ResponseCreateBid{
+		ResponseBid: *createdBid,
+		EndTime:     e.EndTime,
+	}, nil
+}
+
+func checkBidEligibility(e *repositories.AuctionBidEligibility, amount float64) error {
+	if e.Status != "ACTIVE" {
+		return fmt.Errorf("auction is not active")
+	}
+
+	if time.Now().UTC().After(e.EndTime.UTC()) {
+		return fmt.Errorf("auction has already ended")
+	}
+
+	if minBid := e.MinimumBid(); amount < minBid {
+		return fmt.Errorf("bid amount must be at least %.2f", minBid)
+	}
+
+	return nil
+}
+
+// newOutbidError reports a bid that was valid when submitted but lost the race
+// to a bid committed while it waited for the auction row.
+func newOutbidError(e *repositories.AuctionBidEligibility) error {
+	return pkg.NewErrorWithData(
+		fmt.Sprintf("outbid while submitting, current price is now %.2f", e.CurrentPrice),
+		http.StatusConflict,
+		dto.ResponseOutbid{
+			CurrentPrice: e.CurrentPrice,
+			MinNextBid:   e.MinimumBid(),
+		},
+	)
+}
+
+// RetractBid voids a bid its bidder placed by mistake. A bid can only be
+// retracted shortly after it was placed, and not in the closing minutes of
+// the auction.
+func (s *BidService) RetractBid(ctx context.Context, bidID string, userID uuid.UUID) (*dto.ResponseVoidBid, error) {
+	return s.voidBid(ctx, bidID, userID, models.BidRetracted, nil, func(bid *models.Bid, e *repositories.AuctionBidEligibility) error {
+		if bid.UserID != userID {
+			return pkg.NewError("forbidden: you can only retract your own bids", http.StatusForbidden)
+		}
+
+		now := time.Now().UTC()
+		window := s.config.BidRetractionWindow
+		if now.Sub(bid.BidTime.UTC()) > window {
+			return pkg.NewError(fmt.Sprintf("bids can only be retracted within %d minutes of placing them", int(window.Minutes())), http.StatusConflict)
+		}
+		cutoff := s.config.BidRetractionCutoff
+		if !now.Before(e.EndTime.UTC().Add(-cutoff)) {
+			return pkg.NewError(fmt.Sprintf("bids can't be retracted in the last %d minutes of an auction", int(cutoff.Minutes())), http.StatusConflict)
+		}
+		return nil
+	})
+}
+
+// CancelBid lets an admin void any valid bid on a running auction.
+func (s *BidService) CancelBid(ctx context.Context, bidID string, userID uuid.UUID, role string, reason string) (*dto.ResponseVoidBid, error) {
+	if role != string(models.RoleAdmin) {
+		return nil, pkg.NewError("forbidden: only admins can cancel bids", http.StatusForbidden)
+	}
+	return s.voidBid(ctx, bidID, userID, models.BidCancelled, &reason, nil)
+}
+
+// voidBid marks a bid void once allow accepts it and, for English auctions,
+// recomputes the price and leader from the remaining valid bids, all under
+// the auction row lock. The bidder's proxy ceiling is dropped with it so the
+// engine does not bid for them again.
+func (s *BidService) voidBid(
+	ctx context.Context,
+	bidID string,
+	voidedBy uuid.UUID,
+	status models.BidStatus,
+	reason *string,
+	allow func(*models.Bid, *repositories.AuctionBidEligibility) error,
+) (*dto.ResponseVoidBid, error) {
+	bidUUID, err := uuid.Parse(bidID)
+	if err != nil {
+		return nil, pkg.NewError("invalid bid ID format", http.StatusBadRequest)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	bid, err := s.repo.GetForUpdate(ctx, tx, bidUUID)
+	if err != nil {
+		if err.Error() == "bid not found" {
+			return nil, pkg.NewError("bid not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	eligibility, err := s.auctionRepo.LockAuctionForBid(ctx, tx, bid.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if bid.Status != models.BidValid {
+		return nil, pkg.NewError("bid is already void", http.StatusConflict)
+	}
+	if eligibility.Status != string(models.AuctionActive) {
+		return nil, pkg.NewError("bids can only be voided while the auction is active", http.StatusConflict)
+	}
+	if allow != nil {
+		if err := allow(bid, eligibility); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Void(ctx, tx, bid.ID, status, voidedBy, reason); err != nil {
+		return nil, err
+	}
+	if err := s.proxyRepo.DeleteByUser(ctx, tx, bid.AuctionID, bid.UserID); err != nil {
+		return nil, err
+	}
+
+	result := &dto.ResponseVoidBid{
+		ResponseBid: dto.ResponseBid{
+			ID:        bid.ID,
+			AuctionID: bid.AuctionID,
+			UserID:    bid.UserID,
+			Amount:    bid.Amount,
+			Quantity:  bid.Quantity,
+			IsAuto:    bid.IsAuto,
+			Status:    string(status),
+			CreatedAt: bid.BidTime.Format(time.RFC3339),
+		},
+		CurrentPrice:    eligibility.CurrentPrice,
+		CurrentBidderID: eligibility.CurrentBidderID,
+	}
+
+	// Sealed bids never move the price before close, so only English
+	// auctions need their leader recomputed.
+	if eligibility.AuctionType == models.AuctionEnglish && eligibility.Quantity > 1 {
+		price, leader, err := s.auctionRepo.RepriceUnits(ctx, tx, bid.AuctionID)
+		if err != nil {
+			return nil, err
+		}
+		result.CurrentPrice = price
+		result.CurrentBidderID = leader
+	} else if eligibility.AuctionType == models.AuctionEnglish {
+		// A leader who backs out takes their automatic bids with them, and
+		// the remaining ceilings answer whoever leads without them.
+		leaderLeft := eligibility.CurrentBidderID != nil && *eligibility.CurrentBidderID == bid.UserID
+		if leaderLeft {
+			if err := s.repo.VoidByUser(ctx, tx, bid.AuctionID, bid.UserID, status, voidedBy, reason); err != nil {
+				return nil, err
+			}
+		}
+
+		leader, err := s.repo.GetLeadingBid(ctx, tx, bid.AuctionID)
+		if err != nil {
+			return nil, err
+		}
+		if leader != nil && leaderLeft {
+			price, leaderID, _, err := s.resolveProxyBids(ctx, tx, bid.AuctionID, eligibility.BidIncrement, leader.Amount, leader.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve proxy bids: %w", err)
+			}
+			leader.Amount = price
+			leader.UserID = leaderID
+		}
+		if leader == nil {
+			price, err := s.auctionRepo.ResetCurrentPrice(ctx, tx, bid.AuctionID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to reset auction price: %w", err)
+			}
+			result.CurrentPrice = price
+			result.CurrentBidderID = nil
+		} else {
+			if err := s.auctionRepo.UpdateCurrentPriceWithBidder(ctx, tx, bid.AuctionID, leader.Amount, leader.UserID); err != nil {
+				return nil, fmt.Errorf("failed to update auction: %w", err)
+			}
+			result.CurrentPrice = leader.Amount
+			result.CurrentBidderID = &leader.UserID
+		}
+	}
+
+	if err := s.wallet.syncHolds(ctx, tx, bid.AuctionID, nil); err != nil {
+		return nil, err
+	}
+	if err := s.events.record(ctx, tx, bid.AuctionID, websocket.ChangeBidVoided); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return result, nil
 }
 
 func (s *BidService) GetListBidByAuctionID(ctx context.Context, auctionID string) ([]dto.ResponseBidWithUser, error) {
@@ -75,5 +545,17 @@ func (s *BidService) GetListBidByAuctionID(ctx context.Context, auctionID string
 		return nil, fmt.Errorf("invalid auction ID format: %w", err)
 	}
 
-	return s.repo.GetListBidByAuctionID(ctx, auctionUUID)
+	bids, err := s.repo.GetListBidByAuctionID(ctx, auctionUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := s.auctionRepo.GetAuctionForBid(ctx, auctionUUID)
+	if err != nil {
+		return nil, err
+	}
+	if auction.AuctionType.HidesBids(models.AuctionStatus(auction.Status)) {
+		return dto.SealBids(bids), nil
+	}
+	return bids, nil
 }
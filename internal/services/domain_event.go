@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"rebid/internal/config"
+	"rebid/internal/events"
+	"rebid/internal/models"
+	"rebid/internal/repositories"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// relayBatch is how many deliveries one relay pass claims at a time.
+const relayBatch = 100
+
+// DomainEventService records changes to auctions in the outbox, in the same
+// transaction as the change, and relays them to every sink at least once.
+type DomainEventService struct {
+	config *config.Config
+	repo   *repositories.DomainEventRepository
+	sinks  map[string]events.Sink
+}
+
+func NewDomainEventService(cfg *config.Config, repo *repositories.DomainEventRepository, sinks map[string]events.Sink) *DomainEventService {
+	return &DomainEventService{
+		config: cfg,
+		repo:   repo,
+		sinks:  sinks,
+	}
+}
+
+// record queues a change to auctionID for every sink inside tx, so it is
+// only published if tx commits.
+func (s *DomainEventService) record(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, change string) error {
+	return s.recordBids(ctx, tx, auctionID, change, nil)
+}
+
+// recordBids records a change that placed bidIDs, so subscribers can be
+// sent just those bids.
+func (s *DomainEventService) recordBids(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, change string, bidIDs []uuid.UUID) error {
+	if len(s.sinks) == 0 {
+		return nil
+	}
+	sinks := make([]string, 0, len(s.sinks))
+	for sink := range s.sinks {
+		sinks = append(sinks, sink)
+	}
+	sort.Strings(sinks)
+	return s.repo.Record(ctx, tx, auctionID, change, bidIDs, sinks)
+}
+
+// recordAll records the same change to each of auctionIDs.
+func (s *DomainEventService) recordAll(ctx context.Context, tx *sql.Tx, auctionIDs []uuid.UUID, change string) error {
+	for _, id := range auctionIDs {
+		if err := s.record(ctx, tx, id, change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Relay publishes the deliveries that are due, oldest event first, until
+// none are left, and returns how many were published. A failed delivery is
+// retried with the same backoff as notifications until it runs out of
+// attempts.
+func (s *DomainEventService) Relay(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		due, err := s.repo.ClaimDue(ctx, relayBatch, dispatchLease)
+		if err != nil {
+			return published, err
+		}
+		for _, d := range due {
+			if s.publish(ctx, d) {
+				published++
+			}
+		}
+		if len(due) < relayBatch {
+			return published, nil
+		}
+	}
+}
+
+func (s *DomainEventService) publish(ctx context.Context, d models.DomainEventDelivery) bool {
+	sink, ok := s.sinks[d.Sink]
+	if !ok {
+		s.fail(ctx, d, fmt.Errorf("sink %s is not configured", d.Sink), true)
+		return false
+	}
+
+	err := sink.Publish(ctx, events.Event{
+		ID:         d.EventID,
+		Seq:        d.Seq,
+		AuctionID:  d.AuctionID,
+		AuctionSeq: d.AuctionSeq,
+		Change:     d.Change,
+		BidIDs:     d.BidIDs,
+		CreatedAt:  d.CreatedAt,
+	})
+	if err != nil {
+		s.fail(ctx, d, err, d.Attempts >= s.config.EventMaxAttempts)
+		return false
+	}
+
+	if err := s.repo.MarkPublished(ctx, d.EventID, d.Sink); err != nil {
+		log.Printf("domain events: %v", err)
+	}
+	return true
+}
+
+func (s *DomainEventService) fail(ctx context.Context, d models.DomainEventDelivery, cause error, final bool) {
+	var err error
+	if final {
+		log.Printf("domain events: giving up on %s %s to %s after %d attempt(s): %v", d.Change, d.EventID, d.Sink, d.Attempts, cause)
+		err = s.repo.MarkFailed(ctx, d.EventID, d.Sink, cause.Error())
+	} else {
+		err = s.repo.MarkRetry(ctx, d.EventID, d.Sink, cause.Error(), time.Now().Add(retryBackoff(d.Attempts)))
+	}
+	if err != nil {
+		log.Printf("domain events: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/websocket"
+	"rebid/pkg"
+)
+
+type HubService struct {
+	config *config.Config
+	hub    *websocket.Hub
+}
+
+func NewHubService(cfg *config.Config, hub *websocket.Hub) *HubService {
+	return &HubService{
+		config: cfg,
+		hub:    hub,
+	}
+}
+
+// GetMetrics shows admins this node's websocket rooms and what they lost to
+// slow consumers. Every node keeps its own counts.
+func (s *HubService) GetMetrics(ctx context.Context, role string) (*dto.ResponseHubMetrics, error) {
+	if role != string(models.RoleAdmin) {
+		return nil, pkg.NewError("forbidden: only admins can view websocket metrics", http.StatusForbidden)
+	}
+
+	response := s.hub.Metrics()
+	response.NodeID = s.config.NodeID
+	return response, nil
+}
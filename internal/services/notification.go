@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/notify"
+	"rebid/internal/repositories"
+	"rebid/pkg"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// dispatchBatch is how many notifications one dispatch pass claims.
+	dispatchBatch = 50
+	// dispatchLease is how long a claimed notification is held before
+	// another dispatcher may retry it.
+	dispatchLease = time.Minute
+	// retryBase and retryMax bound the exponential backoff between
+	// delivery attempts.
+	retryBase = 30 * time.Second
+	retryMax  = time.Hour
+	// inboxPage is how many notifications an inbox listing returns when the
+	// caller does not ask for fewer.
+	inboxPage = 50
+)
+
+// NotificationService queues notifications in the outbox and delivers them
+// through every configured channel, retrying failed deliveries.
+type NotificationService struct {
+	config   *config.Config
+	db       *sql.DB
+	repo     *repositories.NotificationRepository
+	channels map[string]notify.Notifier
+}
+
+func NewNotificationService(cfg *config.Config, db *sql.DB, repo *repositories.NotificationRepository, channels map[string]notify.Notifier) *NotificationService {
+	return &NotificationService{
+		config:   cfg,
+		db:       db,
+		repo:     repo,
+		channels: channels,
+	}
+}
+
+// channelNames lists the configured channels in a stable order.
+func (s *NotificationService) channelNames() []string {
+	channels := make([]string, 0, len(s.channels))
+	for channel := range s.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// enqueue queues msg for every channel inside tx, so it is only sent if tx
+// commits.
+func (s *NotificationService) enqueue(ctx context.Context, tx *sql.Tx, msg notify.Message) error {
+	if len(s.channels) == 0 {
+		return nil
+	}
+	return s.repo.Enqueue(ctx, tx, msg.UserID, msg.AuctionID, msg.Kind, msg.Subject, msg.Body, s.channelNames())
+}
+
+// enqueueForWatchers queues the same message for everyone watching
+// auctionID inside tx.
+func (s *NotificationService) enqueueForWatchers(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, kind, subject, body string) error {
+	if len(s.channels) == 0 {
+		return nil
+	}
+	return s.repo.EnqueueForWatchers(ctx, tx, auctionID, kind, subject, body, s.channelNames())
+}
+
+// Notify queues msg on its own. It makes the outbox a notify.Notifier for
+// callers with no transaction of their own to write it in.
+func (s *NotificationService) Notify(ctx context.Context, msg notify.Message) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := s.enqueue(ctx, tx, msg); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+func (s *NotificationService) GetNotifications(ctx context.Context, userID uuid.UUID, filter *dto.FilterNotification) ([]dto.ResponseNotification, error) {
+	if filter.Limit <= 0 || filter.Limit > inboxPage {
+		filter.Limit = inboxPage
+	}
+	notifications, err := s.repo.ListInbox(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.ResponseNotification, 0, len(notifications))
+	for i := range notifications {
+		response = append(response, *dto.NewResponseNotification(&notifications[i]))
+	}
+	return response, nil
+}
+
+func (s *NotificationService) GetUnreadCount(ctx context.Context, userID uuid.UUID) (*dto.ResponseUnreadCount, error) {
+	count, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ResponseUnreadCount{Unread: count}, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, notificationID string, userID uuid.UUID) (*dto.ResponseNotification, error) {
+	notificationUUID, err := uuid.Parse(notificationID)
+	if err != nil {
+		return nil, pkg.NewError("invalid notification ID format", http.StatusBadRequest)
+	}
+
+	notification, err := s.repo.MarkRead(ctx, notificationUUID, userID)
+	if err != nil {
+		if err.Error() == "notification not found" {
+			return nil, pkg.NewError("notification not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+	return dto.NewResponseNotification(notification), nil
+}
+
+// MarkAllRead clears the user's unread count and returns what is left
+// unread, which is nothing.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) (*dto.ResponseUnreadCount, error) {
+	if _, err := s.repo.MarkAllRead(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.GetUnreadCount(ctx, userID)
+}
+
+// Dispatch delivers the notifications that are due, until none are left,
+// and returns how many were delivered. A failed delivery is retried with
+// exponential backoff until it runs out of attempts.
+func (s *NotificationService) Dispatch(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		due, err := s.repo.ClaimDue(ctx, dispatchBatch, dispatchLease)
+		if err != nil {
+			return delivered, err
+		}
+		for _, n := range due {
+			if s.deliver(ctx, n) {
+				delivered++
+			}
+		}
+		if len(due) < dispatchBatch {
+			return delivered, nil
+		}
+	}
+}
+
+func (s *NotificationService) deliver(ctx context.Context, n models.OutboxNotification) bool {
+	notifier, ok := s.channels[n.Channel]
+	if !ok {
+		s.fail(ctx, n, fmt.Errorf("channel %s is not configured", n.Channel), true)
+		return false
+	}
+
+	err := notifier.Notify(ctx, notify.Message{
+		ID:        n.ID,
+		UserID:    n.UserID,
+		Kind:      n.Kind,
+		Subject:   n.Subject,
+		Body:      n.Body,
+		AuctionID: n.AuctionID,
+	})
+	if err != nil {
+		s.fail(ctx, n, err, n.Attempts >= s.config.NotifyMaxAttempts)
+		return false
+	}
+
+	if err := s.repo.MarkDelivered(ctx, n.ID); err != nil {
+		log.Printf("notifications: %v", err)
+	}
+	return true
+}
+
+func (s *NotificationService) fail(ctx context.Context, n models.OutboxNotification, cause error, final bool) {
+	var err error
+	if final {
+		log.Printf("notifications: giving up on %s to %s after %d attempt(s): %v", n.Channel, n.UserID, n.Attempts, cause)
+		err = s.repo.MarkFailed(ctx, n.ID, cause.Error())
+	} else {
+		err = s.repo.MarkRetry(ctx, n.ID, cause.Error(), time.Now().Add(retryBackoff(n.Attempts)))
+	}
+	if err != nil {
+		log.Printf("notifications: %v", err)
+	}
+}
+
+// retryBackoff is the wait after the given number of failed attempts.
+func retryBackoff(attempts int) time.Duration {
+	d := retryBase
+	for i := 1; i < attempts && d < retryMax; i++ {
+		d *= 2
+	}
+	return min(d, retryMax)
+}
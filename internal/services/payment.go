@@ -0,0 +1,479 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/notify"
+	"rebid/internal/payments"
+	"rebid/internal/repositories"
+	"rebid/pkg"
+
+	"github.com/google/uuid"
+)
+
+type PaymentService struct {
+	config      *config.Config
+	db          *sql.DB
+	repo        *repositories.PaymentRepository
+	auctionRepo *repositories.AuctionRepository
+	offerRepo   *repositories.SecondChanceOfferRepository
+	wallet      *WalletService
+	gateway     payments.Gateway
+	notifier    *NotificationService
+}
+
+func NewPaymentService(
+	cfg *config.Config,
+	db *sql.DB,
+	repo *repositories.PaymentRepository,
+	auctionRepo *repositories.AuctionRepository,
+	offerRepo *repositories.SecondChanceOfferRepository,
+	wallet *WalletService,
+	gateway payments.Gateway,
+	notifications *NotificationService,
+) *PaymentService {
+	return &PaymentService{
+		config:      cfg,
+		db:          db,
+		repo:        repo,
+		auctionRepo: auctionRepo,
+		offerRepo:   offerRepo,
+		wallet:      wallet,
+		gateway:     gateway,
+		notifier:    notifications,
+	}
+}
+
+// Checkout collects what the winner of an ENDED auction owes: the total is
+// authorized against their payment method and captured straight away. A
+// declined payment is recorded as FAILED and the winner may check out again,
+// unless the item has since been offered to the runner-up.
+// Once captured, the winner's credit hold on the auction is released.
+//
+// The payment is marked AUTHORIZED before the provider is called, so a second
+// checkout of the same result is turned away, and the provider is only ever
+// called with no transaction open. Should recording the capture fail, the
+// provider's payment.captured webhook settles the payment instead.
+func (s *PaymentService) Checkout(ctx context.Context, auctionID string, req *dto.CheckoutRequest, userID uuid.UUID) (*dto.ResponseCheckout, error) {
+	auctionUUID, err := uuid.Parse(auctionID)
+	if err != nil {
+		return nil, pkg.NewError("invalid auction ID format", http.StatusBadRequest)
+	}
+
+	result, err := s.startCheckout(ctx, auctionUUID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := result.BuyerTotal()
+	auth, err := s.gateway.Authorize(ctx, payments.AuthorizeRequest{
+		Reference:     result.ID.String(),
+		Amount:        amount,
+		Currency:      s.config.PaymentCurrency,
+		PaymentMethod: req.PaymentMethod,
+	})
+	if err != nil {
+		// Without a provider reference no webhook can settle this attempt,
+		// so it fails here whatever went wrong.
+		return nil, s.fail(ctx, result, nil, amount, fmt.Errorf("payment gateway: %w", err))
+	}
+	if err := s.repo.AttachProviderRef(ctx, result.ID, auth.ProviderRef); err != nil {
+		return nil, s.fail(ctx, result, nil, amount, err)
+	}
+	result.PaymentProviderRef = &auth.ProviderRef
+
+	if err := s.gateway.Capture(ctx, auth.ProviderRef, amount); err != nil {
+		if errors.Is(err, payments.ErrDeclined) {
+			return nil, s.fail(ctx, result, &auth.ProviderRef, amount, err)
+		}
+		// The capture may still have gone through; the webhook tells.
+		return nil, fmt.Errorf("payment gateway: %w", err)
+	}
+	if err := s.advance(ctx, result, &models.PaymentStatusTransition{
+		FromStatus:  models.PaymentAuthorized,
+		ToStatus:    models.PaymentCaptured,
+		Source:      models.PaymentSourceCheckout,
+		ProviderRef: &auth.ProviderRef,
+		Amount:      &amount,
+	}); err != nil {
+		log.Printf("payments: record capture of %s, leaving it to the webhook: %v", result.ID, err)
+	}
+
+	return &dto.ResponseCheckout{
+		ResultID:      result.ID,
+		AuctionID:     result.AuctionID,
+		Amount:        amount,
+		PaymentStatus: string(*result.PaymentStatus),
+		ProviderRef:   result.PaymentProviderRef,
+	}, nil
+}
+
+// startCheckout checks that userID may pay for auctionID and commits the
+// result as AUTHORIZED before any money moves.
+func (s *PaymentService) startCheckout(ctx context.Context, auctionID, userID uuid.UUID) (*models.AuctionResult, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	state, err := s.auctionRepo.LockForTransition(ctx, tx, auctionID)
+	if err != nil {
+		if err.Error() == "auction not found" {
+			return nil, pkg.NewError("auction not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+	if state.Status != models.AuctionEnded {
+		return nil, pkg.NewError("auction has not ended", http.StatusConflict)
+	}
+	pending, err := s.offerRepo.HasPending(ctx, tx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, pkg.NewError("the item has been offered to another bidder", http.StatusConflict)
+	}
+
+	result, err := s.repo.LockWinnerResult(ctx, tx, auctionID, userID)
+	if err != nil {
+		if err.Error() == "auction result not found" {
+			return nil, pkg.NewError("forbidden: you did not win this auction", http.StatusForbidden)
+		}
+		return nil, err
+	}
+	from := *result.PaymentStatus
+	if !from.CanMoveTo(models.PaymentAuthorized) {
+		return nil, pkg.NewError(fmt.Sprintf("payment is already %s", from), http.StatusConflict)
+	}
+
+	amount := result.BuyerTotal()
+	if err := s.recordStatus(ctx, tx, result, &models.PaymentStatusTransition{
+		FromStatus: from,
+		ToStatus:   models.PaymentAuthorized,
+		Source:     models.PaymentSourceCheckout,
+		Amount:     &amount,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return result, nil
+}
+
+// fail records a checkout that went no further as FAILED, so the attempt
+// stays in the payment history and the winner may check out again. A
+// provider refusal is returned as 402, any other cause as is.
+func (s *PaymentService) fail(ctx context.Context, result *models.AuctionResult, providerRef *string, amount float64, cause error) error {
+	detail := cause.Error()
+	if err := s.advance(ctx, result, &models.PaymentStatusTransition{
+		FromStatus:  models.PaymentAuthorized,
+		ToStatus:    models.PaymentFailed,
+		Source:      models.PaymentSourceCheckout,
+		ProviderRef: providerRef,
+		Amount:      &amount,
+		Detail:      &detail,
+	}); err != nil {
+		log.Printf("payments: record failed checkout of %s: %v", result.ID, err)
+	}
+	if errors.Is(cause, payments.ErrDeclined) {
+		return pkg.NewError(detail, http.StatusPaymentRequired)
+	}
+	return cause
+}
+
+// advance applies t to result in a transaction of its own, unless a webhook
+// has moved the payment on from t.FromStatus in the meantime.
+func (s *PaymentService) advance(ctx context.Context, result *models.AuctionResult, t *models.PaymentStatusTransition) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the auction before its result, in the same order as checkout.
+	if _, err := s.auctionRepo.LockForTransition(ctx, tx, result.AuctionID); err != nil {
+		return err
+	}
+	locked, err := s.repo.LockResult(ctx, tx, result.ID)
+	if err != nil {
+		return err
+	}
+	if *locked.PaymentStatus != t.FromStatus {
+		return fmt.Errorf("payment is already %s", *locked.PaymentStatus)
+	}
+	if err := s.recordStatus(ctx, tx, locked, t); err != nil {
+		return err
+	}
+	if err := s.wallet.syncHolds(ctx, tx, locked.AuctionID, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	*result = *locked
+	return nil
+}
+
+// HandleWebhook applies a signed provider event to the result it refers to.
+// Redelivered events, and events that would not move the payment forward,
+// are acknowledged without effect.
+func (s *PaymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.gateway.VerifyWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			return pkg.NewError("invalid webhook signature", http.StatusUnauthorized)
+		}
+		return pkg.NewError(err.Error(), http.StatusBadRequest)
+	}
+
+	var to models.PaymentStatus
+	switch event.Type {
+	case payments.EventPaymentCaptured:
+		to = models.PaymentCaptured
+	case payments.EventPaymentFailed:
+		to = models.PaymentFailed
+	case payments.EventPaymentRefunded:
+		to = models.PaymentRefunded
+	default:
+		return nil
+	}
+
+	found, err := s.repo.FindResultByProviderRef(ctx, event.ProviderRef)
+	if err != nil {
+		if err.Error() == "auction result not found" {
+			return pkg.NewError("payment not found", http.StatusNotFound)
+		}
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the auction before its result, in the same order as checkout.
+	if _, err := s.auctionRepo.LockForTransition(ctx, tx, found.AuctionID); err != nil {
+		return err
+	}
+	result, err := s.repo.LockResult(ctx, tx, found.ID)
+	if err != nil {
+		return err
+	}
+
+	fresh, err := s.repo.MarkWebhookEvent(ctx, tx, event.ID)
+	if err != nil {
+		return err
+	}
+	from := *result.PaymentStatus
+	// A capture reported late must not undo a refund under way.
+	if from == models.PaymentRefundPending && to == models.PaymentCaptured {
+		fresh = false
+	}
+	if fresh && from.CanMoveTo(to) {
+		if err := s.recordStatus(ctx, tx, result, &models.PaymentStatusTransition{
+			FromStatus:  from,
+			ToStatus:    to,
+			Source:      models.PaymentSourceWebhook,
+			ProviderRef: &event.ProviderRef,
+			Amount:      &event.Amount,
+		}); err != nil {
+			return err
+		}
+		if err := s.wallet.syncHolds(ctx, tx, result.AuctionID, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// RefundPayment lets an admin refund a captured payment in full. Like
+// checkout, the payment is marked REFUND_PENDING before the provider is
+// called and no transaction is open while it is; should recording the
+// refund fail, the provider's payment.refunded webhook settles it instead.
+func (s *PaymentService) RefundPayment(ctx context.Context, resultID string, req *dto.RefundPaymentRequest, adminID uuid.UUID, role string) (*dto.ResponseCheckout, error) {
+	if role != string(models.RoleAdmin) {
+		return nil, pkg.NewError("forbidden: only admins can refund payments", http.StatusForbidden)
+	}
+	resultUUID, err := uuid.Parse(resultID)
+	if err != nil {
+		return nil, pkg.NewError("invalid result ID format", http.StatusBadRequest)
+	}
+
+	result, err := s.startRefund(ctx, resultUUID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := result.BuyerTotal()
+	if err := s.gateway.Refund(ctx, *result.PaymentProviderRef, amount); err != nil {
+		if !errors.Is(err, payments.ErrDeclined) {
+			// The refund may still have gone through; the webhook tells.
+			return nil, fmt.Errorf("payment gateway: %w", err)
+		}
+		detail := err.Error()
+		if err := s.advance(ctx, result, &models.PaymentStatusTransition{
+			FromStatus: models.PaymentRefundPending,
+			ToStatus:   models.PaymentCaptured,
+			Source:     models.PaymentSourceAdmin,
+			Amount:     &amount,
+			Detail:     &detail,
+		}); err != nil {
+			log.Printf("payments: record declined refund of %s: %v", result.ID, err)
+		}
+		return nil, pkg.NewError(detail, http.StatusPaymentRequired)
+	}
+	if err := s.advance(ctx, result, &models.PaymentStatusTransition{
+		FromStatus: models.PaymentRefundPending,
+		ToStatus:   models.PaymentRefunded,
+		Source:     models.PaymentSourceAdmin,
+		Amount:     &amount,
+		Detail:     req.Reason,
+	}); err != nil {
+		log.Printf("payments: record refund of %s, leaving it to the webhook: %v", result.ID, err)
+	}
+
+	return &dto.ResponseCheckout{
+		ResultID:      result.ID,
+		AuctionID:     result.AuctionID,
+		Amount:        amount,
+		PaymentStatus: string(*result.PaymentStatus),
+		ProviderRef:   result.PaymentProviderRef,
+	}, nil
+}
+
+// startRefund checks that resultID has a captured payment and commits it as
+// REFUND_PENDING before any money moves.
+func (s *PaymentService) startRefund(ctx context.Context, resultID uuid.UUID, req *dto.RefundPaymentRequest) (*models.AuctionResult, error) {
+	found, err := s.repo.GetResult(ctx, resultID)
+	if err != nil {
+		if err.Error() == "auction result not found" {
+			return nil, pkg.NewError("auction result not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the auction before its result, in the same order as checkout.
+	if _, err := s.auctionRepo.LockForTransition(ctx, tx, found.AuctionID); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.LockResult(ctx, tx, found.ID)
+	if err != nil {
+		return nil, err
+	}
+	if result.PaymentStatus == nil || !result.PaymentStatus.CanMoveTo(models.PaymentRefundPending) {
+		return nil, pkg.NewError("only captured payments can be refunded", http.StatusConflict)
+	}
+
+	amount := result.BuyerTotal()
+	if err := s.recordStatus(ctx, tx, result, &models.PaymentStatusTransition{
+		FromStatus: models.PaymentCaptured,
+		ToStatus:   models.PaymentRefundPending,
+		Source:     models.PaymentSourceAdmin,
+		Amount:     &amount,
+		Detail:     req.Reason,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return result, nil
+}
+
+// recordStatus moves result to a new payment status and queues the
+// notifications for it in the same transaction: the winner hears about a
+// capture, a decline or a refund, and the seller about a capture.
+func (s *PaymentService) recordStatus(ctx context.Context, tx *sql.Tx, result *models.AuctionResult, t *models.PaymentStatusTransition) error {
+	if err := s.repo.RecordStatus(ctx, tx, result, t); err != nil {
+		return err
+	}
+	if result.WinnerID == nil {
+		return nil
+	}
+
+	var messages []notify.Message
+	switch t.ToStatus {
+	case models.PaymentCaptured:
+		messages = append(messages,
+			notify.Message{
+				UserID:  *result.WinnerID,
+				Kind:    notify.KindPaymentCaptured,
+				Subject: "Your payment went through",
+				Body:    fmt.Sprintf("We received your payment of %.2f %s.", result.BuyerTotal(), s.config.PaymentCurrency),
+			},
+			notify.Message{
+				UserID:  result.SellerID,
+				Kind:    notify.KindPaymentCaptured,
+				Subject: "Your buyer has paid",
+				Body:    "The winner of your auction has paid. You can now ship the item.",
+			},
+		)
+	case models.PaymentFailed:
+		messages = append(messages, notify.Message{
+			UserID:  *result.WinnerID,
+			Kind:    notify.KindPaymentFailed,
+			Subject: "Your payment was declined",
+			Body:    "Your payment could not be completed. Please check out again with another payment method.",
+		})
+	case models.PaymentRefunded:
+		messages = append(messages, notify.Message{
+			UserID:  *result.WinnerID,
+			Kind:    notify.KindPaymentRefunded,
+			Subject: "Your payment was refunded",
+			Body:    fmt.Sprintf("Your payment of %.2f %s has been refunded.", result.BuyerTotal(), s.config.PaymentCurrency),
+		})
+	}
+	for _, msg := range messages {
+		msg.AuctionID = &result.AuctionID
+		if err := s.notifier.enqueue(ctx, tx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPaymentHistory returns the payment status changes of a result to its
+// seller, its winner or an admin.
+func (s *PaymentService) GetPaymentHistory(ctx context.Context, resultID string, userID uuid.UUID, role string) ([]dto.ResponsePaymentTransition, error) {
+	resultUUID, err := uuid.Parse(resultID)
+	if err != nil {
+		return nil, pkg.NewError("invalid result ID format", http.StatusBadRequest)
+	}
+
+	result, err := s.repo.GetResult(ctx, resultUUID)
+	if err != nil {
+		if err.Error() == "auction result not found" {
+			return nil, pkg.NewError("auction result not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+	isWinner := result.WinnerID != nil && *result.WinnerID == userID
+	if role != string(models.RoleAdmin) && result.SellerID != userID && !isWinner {
+		return nil, pkg.NewError("forbidden: you are not a party to this auction", http.StatusForbidden)
+	}
+
+	return s.repo.ListTransitions(ctx, resultUUID)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"rebid/internal/models"
+	"rebid/pkg"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// resolveProxyBids lets the stored ceilings answer a bid that just made leader
+// the high bidder at price. Automatic bids raise by the auction's increment
+// policy and every one of them is recorded in the bids table, so the history
+// reads like two people bidding against each other. Ties between equal
+// ceilings go to the one set first. The automatic bids are returned with the
+// final price and leader.
+func (s *BidService) resolveProxyBids(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, increment models.BidIncrement, price float64, leader uuid.UUID) (float64, uuid.UUID, []uuid.UUID, error) {
+	proxies, err := s.proxyRepo.GetByAuctionID(ctx, tx, auctionID)
+	if err != nil {
+		return 0, uuid.Nil, nil, err
+	}
+
+	ceilings := make(map[uuid.UUID]float64, len(proxies))
+	setAt := make(map[uuid.UUID]time.Time, len(proxies))
+	for _, p := range proxies {
+		ceilings[p.UserID] = p.MaxAmount
+		setAt[p.UserID] = p.UpdatedAt
+	}
+
+	var placed []uuid.UUID
+	place := func(userID uuid.UUID, amount float64) error {
+		price = amount
+		bidID, err := s.repo.CreateAutoBid(ctx, tx, auctionID, userID, amount)
+		if err != nil {
+			return err
+		}
+		placed = append(placed, bidID)
+		return nil
+	}
+
+	for {
+		var challenger *models.ProxyBid
+		for i := range proxies {
+			if proxies[i].UserID != leader && proxies[i].MaxAmount >= increment.MinimumBid(price, true) {
+				challenger = &proxies[i]
+				break
+			}
+		}
+		if challenger == nil {
+			return price, leader, placed, nil
+		}
+
+		leaderMax := price
+		if m, ok := ceilings[leader]; ok && m > price {
+			leaderMax = m
+		}
+
+		switch {
+		case challenger.MaxAmount > leaderMax:
+			if leaderMax > price {
+				if err := place(leader, leaderMax); err != nil {
+					return 0, uuid.Nil, nil, err
+				}
+			}
+			if err := place(challenger.UserID, math.Min(challenger.MaxAmount, pkg.RoundPrice(price+increment.Step(price)))); err != nil {
+				return 0, uuid.Nil, nil, err
+			}
+			leader = challenger.UserID
+		case challenger.MaxAmount == leaderMax:
+			if set, ok := setAt[leader]; ok && !set.After(challenger.UpdatedAt) {
+				if err := place(leader, leaderMax); err != nil {
+					return 0, uuid.Nil, nil, err
+				}
+			} else {
+				if err := place(challenger.UserID, leaderMax); err != nil {
+					return 0, uuid.Nil, nil, err
+				}
+				leader = challenger.UserID
+			}
+		default:
+			if err := place(challenger.UserID, challenger.MaxAmount); err != nil {
+				return 0, uuid.Nil, nil, err
+			}
+			if err := place(leader, math.Min(leaderMax, pkg.RoundPrice(price+increment.Step(price)))); err != nil {
+				return 0, uuid.Nil, nil, err
+			}
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/notify"
+	"rebid/internal/repositories"
+	"rebid/pkg"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SecondChanceService struct {
+	config      *config.Config
+	db          *sql.DB
+	repo        *repositories.SecondChanceOfferRepository
+	auctionRepo *repositories.AuctionRepository
+	bidRepo     *repositories.BidRepository
+	resultRepo  *repositories.AuctionResultRepository
+	wallet      *WalletService
+	notifier    *NotificationService
+}
+
+func NewSecondChanceService(
+	cfg *config.Config,
+	db *sql.DB,
+	repo *repositories.SecondChanceOfferRepository,
+	auctionRepo *repositories.AuctionRepository,
+	bidRepo *repositories.BidRepository,
+	resultRepo *repositories.AuctionResultRepository,
+	wallet *WalletService,
+	notifications *NotificationService,
+) *SecondChanceService {
+	return &SecondChanceService{
+		config:      cfg,
+		db:          db,
+		repo:        repo,
+		auctionRepo: auctionRepo,
+		bidRepo:     bidRepo,
+		resultRepo:  resultRepo,
+		wallet:      wallet,
+		notifier:    notifications,
+	}
+}
+
+// CreateOffer lets the seller, or an admin, offer an item whose winner has
+// not paid to the next highest bidder.
+func (s *SecondChanceService) CreateOffer(ctx context.Context, auctionID string, userID uuid.UUID, role string) (*models.SecondChanceOffer, error) {
+	auctionUUID, err := uuid.Parse(auctionID)
+	if err != nil {
+		return nil, pkg.NewError("invalid auction ID format", http.StatusBadRequest)
+	}
+
+	return s.offer(ctx, auctionUUID, models.OfferSourceSeller, func(seller uuid.UUID) error {
+		if role != string(models.RoleAdmin) && seller != userID {
+			return pkg.NewError("forbidden: you don't own this auction", http.StatusForbidden)
+		}
+		return nil
+	}, &userID)
+}
+
+// OfferLapsed is one pass of the second-chance worker: it expires offers
+// nobody answered, then offers every item whose winner missed the payment
+// deadline to the next highest bidder. Items without a runner-up are left
+// with their winner.
+func (s *SecondChanceService) OfferLapsed(ctx context.Context) ([]models.SecondChanceOffer, error) {
+	if err := s.expireStale(ctx); err != nil {
+		return nil, err
+	}
+
+	auctionIDs, err := s.repo.ListLapsedAuctions(ctx, s.config.PaymentDeadline)
+	if err != nil {
+		return nil, err
+	}
+
+	var offers []models.SecondChanceOffer
+	for _, auctionID := range auctionIDs {
+		offer, err := s.offer(ctx, auctionID, models.OfferSourceTimeout, nil, nil)
+		if err != nil {
+			if e, ok := err.(*pkg.AppError); !ok || e.StatusCode != http.StatusNotFound {
+				log.Printf("second chance: offer auction %s: %v", auctionID, err)
+			}
+			continue
+		}
+		offers = append(offers, *offer)
+	}
+	return offers, nil
+}
+
+// offer creates an offer for the runner-up of a sold single-unit auction at
+// their last bid. authorize, when set, is checked against the seller once
+// the auction is locked.
+func (s *SecondChanceService) offer(ctx context.Context, auctionID uuid.UUID, source models.OfferSource, authorize func(seller uuid.UUID) error, createdBy *uuid.UUID) (*models.SecondChanceOffer, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	state, err := s.auctionRepo.LockForTransition(ctx, tx, auctionID)
+	if err != nil {
+		if err.Error() == "auction not found" {
+			return nil, pkg.NewError("auction not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+	if authorize != nil {
+		if err := authorize(state.CreatedBy); err != nil {
+			return nil, err
+		}
+	}
+	if state.Status != models.AuctionEnded {
+		return nil, pkg.NewError("auction has not ended", http.StatusConflict)
+	}
+
+	result, err := s.repo.LockSoldResult(ctx, tx, auctionID)
+	if err != nil {
+		if err.Error() == "auction result not found" {
+			return nil, pkg.NewError("second-chance offers are only available on sold single-item auctions", http.StatusConflict)
+		}
+		return nil, err
+	}
+	if !isUnpaid(result) {
+		return nil, pkg.NewError("the winner has already paid", http.StatusConflict)
+	}
+	pending, err := s.repo.HasPending(ctx, tx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, pkg.NewError("a second-chance offer is already pending", http.StatusConflict)
+	}
+
+	bid, err := s.bidRepo.GetRunnerUpBid(ctx, tx, auctionID)
+	if err != nil {
+		return nil, err
+	}
+	if bid == nil {
+		return nil, pkg.NewError("no runner-up bid to offer the item to", http.StatusNotFound)
+	}
+
+	offer := &models.SecondChanceOffer{
+		AuctionID:        auctionID,
+		ResultID:         result.ID,
+		BidID:            bid.ID,
+		UserID:           bid.UserID,
+		PreviousWinnerID: result.WinnerID,
+		Amount:           bid.Amount,
+		Source:           source,
+		CreatedBy:        createdBy,
+		ExpiresAt:        time.Now().Add(s.config.SecondChanceOfferTTL),
+	}
+	if err := s.repo.Create(ctx, tx, offer); err != nil {
+		if err.Error() == "offer already pending" {
+			return nil, pkg.NewError("a second-chance offer is already pending", http.StatusConflict)
+		}
+		return nil, err
+	}
+	if err := s.notifier.enqueue(ctx, tx, notify.Message{
+		UserID:    offer.UserID,
+		Kind:      notify.KindSecondChanceOffer,
+		Subject:   "You have a second chance to buy an item",
+		Body:      fmt.Sprintf("The winner did not pay. You can buy the item at your last bid of %.2f until %s.", offer.Amount, offer.ExpiresAt.Format(time.RFC1123)),
+		AuctionID: &offer.AuctionID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return offer, nil
+}
+
+// AcceptOffer makes the runner-up the winner at their offered price. The
+// settlement is reassigned to them and the payment starts over; on a deposit
+// auction their credit is held for it instead of the lapsed winner's.
+func (s *SecondChanceService) AcceptOffer(ctx context.Context, offerID string, userID uuid.UUID) (*models.SecondChanceOffer, error) {
+	return s.respond(ctx, offerID, userID, models.OfferAccepted, func(tx *sql.Tx, offer *models.SecondChanceOffer) error {
+		result, err := s.repo.LockSoldResult(ctx, tx, offer.AuctionID)
+		if err != nil && err.Error() != "auction result not found" {
+			return err
+		}
+		if result == nil || result.ID != offer.ResultID || !isUnpaid(result) {
+			return pkg.NewError("the item is no longer available", http.StatusConflict)
+		}
+		if err := s.resultRepo.Reassign(ctx, tx, result.ID, *result.PaymentStatus, userID, offer.Amount, s.config.BuyerPremiumPercent, s.config.SellerFeePercent); err != nil {
+			return err
+		}
+		if err := s.auctionRepo.ReassignWinner(ctx, tx, offer.AuctionID, userID, offer.Amount); err != nil {
+			return err
+		}
+		return s.wallet.syncHolds(ctx, tx, offer.AuctionID, &userID)
+	})
+}
+
+func (s *SecondChanceService) DeclineOffer(ctx context.Context, offerID string, userID uuid.UUID) (*models.SecondChanceOffer, error) {
+	return s.respond(ctx, offerID, userID, models.OfferDeclined, nil)
+}
+
+// respond closes a pending offer on behalf of the bidder it was made to,
+// applying apply in the same transaction.
+func (s *SecondChanceService) respond(ctx context.Context, offerID string, userID uuid.UUID, status models.OfferStatus, apply func(*sql.Tx, *models.SecondChanceOffer) error) (*models.SecondChanceOffer, error) {
+	offerUUID, err := uuid.Parse(offerID)
+	if err != nil {
+		return nil, pkg.NewError("invalid offer ID format", http.StatusBadRequest)
+	}
+
+	found, err := s.repo.GetByID(ctx, offerUUID)
+	if err != nil {
+		if err.Error() == "offer not found" {
+			return nil, pkg.NewError("offer not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+	if found.UserID != userID {
+		return nil, pkg.NewError("forbidden: this offer was not made to you", http.StatusForbidden)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the auction before the offer, in the same order as creating one.
+	state, err := s.auctionRepo.LockForTransition(ctx, tx, found.AuctionID)
+	if err != nil {
+		return nil, err
+	}
+	offer, err := s.repo.LockByID(ctx, tx, offerUUID)
+	if err != nil {
+		return nil, err
+	}
+	if offer.Status != models.OfferPending {
+		return nil, pkg.NewError(fmt.Sprintf("offer is already %s", offer.Status), http.StatusConflict)
+	}
+	if !time.Now().Before(offer.ExpiresAt) {
+		return nil, pkg.NewError("offer has expired", http.StatusGone)
+	}
+
+	if apply != nil {
+		if err := apply(tx, offer); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.Respond(ctx, tx, offer.ID, status); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	offer.Status = status
+	offer.RespondedAt = &now
+	kind, subject := notify.KindSecondChanceDeclined, "Second-chance offer declined"
+	if status == models.OfferAccepted {
+		kind, subject = notify.KindSecondChanceAccepted, "Second-chance offer accepted"
+	}
+	if err := s.notifySeller(ctx, tx, state.CreatedBy, *offer, kind, subject); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return offer, nil
+}
+
+// GetAuctionOffers lists the offers made on an auction to its seller or an
+// admin.
+func (s *SecondChanceService) GetAuctionOffers(ctx context.Context, auctionID string, userID uuid.UUID, role string) ([]dto.ResponseSecondChanceOffer, error) {
+	auctionUUID, err := uuid.Parse(auctionID)
+	if err != nil {
+		return nil, pkg.NewError("invalid auction ID format", http.StatusBadRequest)
+	}
+
+	auction, err := s.auctionRepo.GetByID(ctx, auctionUUID)
+	if err != nil {
+		if err.Error() == "auction not found" {
+			return nil, pkg.NewError("auction not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+	if role != string(models.RoleAdmin) && auction.CreatedBy != userID {
+		return nil, pkg.NewError("forbidden: you don't own this auction", http.StatusForbidden)
+	}
+
+	return s.repo.ListByAuctionID(ctx, auctionUUID)
+}
+
+func (s *SecondChanceService) GetUserOffers(ctx context.Context, userID uuid.UUID) ([]dto.ResponseSecondChanceOffer, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// expireStale expires the offers nobody answered in time and tells their
+// sellers in the same transaction.
+func (s *SecondChanceService) expireStale(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	expired, err := s.repo.ExpireStale(ctx, tx)
+	if err != nil {
+		return err
+	}
+	for _, offer := range expired {
+		auction, err := s.auctionRepo.GetByID(ctx, offer.AuctionID)
+		if err != nil {
+			return err
+		}
+		if err := s.notifySeller(ctx, tx, auction.CreatedBy, offer, notify.KindSecondChanceDeclined, "Second-chance offer expired"); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// notifySeller queues the news of an offer for the seller inside tx.
+func (s *SecondChanceService) notifySeller(ctx context.Context, tx *sql.Tx, sellerID uuid.UUID, offer models.SecondChanceOffer, kind, subject string) error {
+	return s.notifier.enqueue(ctx, tx, notify.Message{
+		UserID:    sellerID,
+		Kind:      kind,
+		Subject:   subject,
+		Body:      fmt.Sprintf("Your offer of the item at %.2f is now %s.", offer.Amount, offer.Status),
+		AuctionID: &offer.AuctionID,
+	})
+}
+
+// isUnpaid reports whether a settlement's winner still owes the whole
+// amount.
+func isUnpaid(result *models.AuctionResult) bool {
+	return result.PaymentStatus != nil &&
+		(*result.PaymentStatus == models.PaymentPending || *result.PaymentStatus == models.PaymentFailed)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/repositories"
+	"rebid/pkg"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+type WalletService struct {
+	db     *sql.DB
+	repo   *repositories.LedgerRepository
+	config *config.Config
+}
+
+func NewWalletService(cfg *config.Config, db *sql.DB, repo *repositories.LedgerRepository) *WalletService {
+	return &WalletService{
+		db:     db,
+		repo:   repo,
+		config: cfg,
+	}
+}
+
+func (s *WalletService) GetWallet(ctx context.Context, userID uuid.UUID) (*dto.ResponseWallet, error) {
+	available, held, err := s.repo.GetBalances(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	holds, err := s.repo.ListHoldsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ResponseWallet{
+		UserID:    userID,
+		Available: available,
+		Held:      held,
+		Holds:     holds,
+	}, nil
+}
+
+func (s *WalletService) GetEntries(ctx context.Context, userID uuid.UUID, limit int) ([]dto.ResponseLedgerEntry, error) {
+	return s.repo.ListEntries(ctx, userID, limit)
+}
+
+// GrantCredit lets an admin pre-authorize credit for a user to bid with.
+func (s *WalletService) GrantCredit(ctx context.Context, req *dto.GrantCreditRequest, adminID uuid.UUID, role string) (*dto.ResponseWallet, error) {
+	if role != string(models.RoleAdmin) {
+		return nil, pkg.NewError("forbidden: only admins can grant credit", http.StatusForbidden)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	t := &models.LedgerTransaction{
+		Kind:      models.LedgerCredit,
+		UserID:    req.UserID,
+		CreatedBy: &adminID,
+		Memo:      req.Memo,
+	}
+	if err := s.repo.Transfer(ctx, tx, t, models.AccountFunding, models.AccountAvailable, pkg.RoundPrice(req.Amount)); err != nil {
+		if err.Error() == "user not found" {
+			return nil, pkg.NewError("user not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+
+	return s.GetWallet(ctx, req.UserID)
+}
+
+// checkCredit rejects a commitment of amount on an auction that the user's
+// available credit, plus what they already hold there, cannot cover.
+func (s *WalletService) checkCredit(ctx context.Context, tx *sql.Tx, auctionID, userID uuid.UUID, amount float64) error {
+	available, err := s.repo.GetAvailable(ctx, tx, userID)
+	if err != nil {
+		return err
+	}
+	held, err := s.repo.GetHold(ctx, tx, auctionID, userID)
+	if err != nil {
+		return err
+	}
+	if pkg.RoundPrice(available+held) < pkg.RoundPrice(amount) {
+		return newInsufficientCreditError(available + held)
+	}
+	return nil
+}
+
+// syncHolds moves held credit on an auction to match what its current state
+// requires, releasing bidders who were outbid. The caller holds the auction
+// row lock. Only actor, the user whose action changed the auction, is
+// refused for lacking credit; anyone else's hold grows only as far as their
+// available credit allows.
+func (s *WalletService) syncHolds(ctx context.Context, tx *sql.Tx, auctionID uuid.UUID, actor *uuid.UUID) error {
+	desired, err := s.repo.DesiredHolds(ctx, tx, auctionID)
+	if err != nil {
+		return err
+	}
+	current, err := s.repo.ListHolds(ctx, tx, auctionID)
+	if err != nil {
+		return err
+	}
+
+	// Visit users in a fixed order so concurrent syncs lock their accounts
+	// consistently.
+	users := make([]uuid.UUID, 0, len(desired)+len(current))
+	for userID := range current {
+		users = append(users, userID)
+	}
+	for userID := range desired {
+		if _, ok := current[userID]; !ok {
+			users = append(users, userID)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].String() < users[j].String() })
+
+	for _, userID := range users {
+		want, have := pkg.RoundPrice(desired[userID]), current[userID]
+		if want == have {
+			continue
+		}
+
+		t := &models.LedgerTransaction{UserID: userID, AuctionID: &auctionID, CreatedBy: actor}
+		if want < have {
+			t.Kind = models.LedgerRelease
+			if err := s.repo.Transfer(ctx, tx, t, models.AccountHeld, models.AccountAvailable, pkg.RoundPrice(have-want)); err != nil {
+				return err
+			}
+		} else {
+			isActor := actor != nil && *actor == userID
+			need := pkg.RoundPrice(want - have)
+			// Read before the transfer: a failed transfer aborts tx.
+			available, err := s.repo.GetAvailable(ctx, tx, userID)
+			if err != nil {
+				return err
+			}
+			if !isActor {
+				need = min(need, available)
+				if need <= 0 {
+					continue
+				}
+			}
+			t.Kind = models.LedgerHold
+			if err := s.repo.Transfer(ctx, tx, t, models.AccountAvailable, models.AccountHeld, need); err != nil {
+				if err.Error() == "insufficient credit" {
+					return newInsufficientCreditError(pkg.RoundPrice(available + have))
+				}
+				return err
+			}
+			want = pkg.RoundPrice(have + need)
+		}
+
+		if err := s.repo.SetHold(ctx, tx, auctionID, userID, want); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newInsufficientCreditError(available float64) error {
+	return pkg.NewError(fmt.Sprintf("insufficient credit, %.2f available for this auction", available), http.StatusPaymentRequired)
+}
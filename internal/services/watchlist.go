@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/notify"
+	"rebid/internal/repositories"
+	"rebid/pkg"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WatchlistService struct {
+	config      *config.Config
+	repo        *repositories.WatchlistRepository
+	auctionRepo *repositories.AuctionRepository
+	notifier    notify.Notifier
+}
+
+func NewWatchlistService(cfg *config.Config, repo *repositories.WatchlistRepository, auctionRepo *repositories.AuctionRepository, notifier notify.Notifier) *WatchlistService {
+	return &WatchlistService{
+		config:      cfg,
+		repo:        repo,
+		auctionRepo: auctionRepo,
+		notifier:    notifier,
+	}
+}
+
+func (s *WatchlistService) GetWatchlist(ctx context.Context, userID uuid.UUID) ([]dto.ResponseWatchlistItem, error) {
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// WatchAuction adds an auction that has not finished yet to the user's
+// watchlist.
+func (s *WatchlistService) WatchAuction(ctx context.Context, req *dto.WatchAuctionRequest, userID uuid.UUID) ([]dto.ResponseWatchlistItem, error) {
+	auction, err := s.auctionRepo.GetByID(ctx, req.AuctionID)
+	if err != nil {
+		if err.Error() == "auction not found" {
+			return nil, pkg.NewError("auction not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+	if auction.Status == string(models.AuctionEnded) || auction.Status == string(models.AuctionCancelled) {
+		return nil, pkg.NewError("auction has already finished", http.StatusConflict)
+	}
+
+	if err := s.repo.Add(ctx, userID, req.AuctionID); err != nil {
+		if err.Error() == "auction not found" {
+			return nil, pkg.NewError("auction not found", http.StatusNotFound)
+		}
+		return nil, err
+	}
+	return s.repo.ListByUser(ctx, userID)
+}
+
+func (s *WatchlistService) UnwatchAuction(ctx context.Context, auctionID string, userID uuid.UUID) error {
+	auctionUUID, err := uuid.Parse(auctionID)
+	if err != nil {
+		return pkg.NewError("invalid auction ID format", http.StatusBadRequest)
+	}
+
+	if err := s.repo.Remove(ctx, userID, auctionUUID); err != nil {
+		if err.Error() == "auction not watched" {
+			return pkg.NewError("auction is not on your watchlist", http.StatusNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// SendReminders notifies watchers of every auction that has crossed a
+// reminder threshold. A watcher who crossed several thresholds since the
+// last pass, for instance by watching late, only hears about the nearest.
+func (s *WatchlistService) SendReminders(ctx context.Context) (int, error) {
+	reminders, err := s.repo.ClaimDueReminders(ctx, s.config.ReminderThresholds)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i, rem := range reminders {
+		// Reminders come ordered by watcher with the nearest threshold first.
+		if i > 0 && reminders[i-1].UserID == rem.UserID && reminders[i-1].AuctionID == rem.AuctionID {
+			continue
+		}
+
+		name := "An auction you watch"
+		if rem.Description != nil && *rem.Description != "" {
+			name = *rem.Description
+		}
+		msg := notify.Message{
+			UserID:    rem.UserID,
+			Kind:      notify.KindAuctionEndingSoon,
+			Subject:   "Auction ending soon",
+			Body:      fmt.Sprintf("%s ends in less than %s, at %s.", name, formatThreshold(rem.Threshold), rem.EndTime.Format(time.RFC1123)),
+			AuctionID: &rem.AuctionID,
+		}
+		if err := s.notifier.Notify(ctx, msg); err != nil {
+			log.Printf("watchlist: notify %s: %v", rem.UserID, err)
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// formatThreshold renders a reminder threshold in the largest whole unit
+// that fits it, such as "24 hours" or "5 minutes".
+func formatThreshold(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return pluralize(int(d/time.Hour), "hour")
+	case d%time.Minute == 0:
+		return pluralize(int(d/time.Minute), "minute")
+	default:
+		return d.String()
+	}
+}
+
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
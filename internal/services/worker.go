@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/repositories"
+	"rebid/pkg"
+)
+
+type WorkerService struct {
+	config *config.Config
+	repo   *repositories.WorkerLeaseRepository
+}
+
+func NewWorkerService(cfg *config.Config, repo *repositories.WorkerLeaseRepository) *WorkerService {
+	return &WorkerService{
+		config: cfg,
+		repo:   repo,
+	}
+}
+
+// GetStatus shows admins which node leads each scheduled job.
+func (s *WorkerService) GetStatus(ctx context.Context, role string) (*dto.ResponseWorkerStatus, error) {
+	if role != string(models.RoleAdmin) {
+		return nil, pkg.NewError("forbidden: only admins can view worker status", http.StatusForbidden)
+	}
+
+	leases, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ResponseWorkerStatus{
+		NodeID: s.config.NodeID,
+		Jobs:   leases,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Rooms a message can be addressed to.
+const (
+	RoomAuction = "auction"
+	RoomEvent   = "event"
+	RoomUser    = "user"
+)
+
+// Envelope is a message addressed to one room of every hub.
+type Envelope struct {
+	Room    string          `json:"room"`
+	ID      uuid.UUID       `json:"id"`
+	Message json.RawMessage `json:"message"`
+}
+
+// Backplane carries hub messages to every hub subscribed to it, the one that
+// published them included, so replicas behind a load balancer reach each
+// other's subscribers.
+type Backplane interface {
+	Publish(ctx context.Context, e Envelope) error
+	// Subscribe registers a function every published message is delivered
+	// to, in the order it was published.
+	Subscribe(deliver func(Envelope))
+}
+
+// MemoryBackplane delivers messages to the hubs of this process only, for a
+// single node.
+type MemoryBackplane struct {
+	mu          sync.RWMutex
+	subscribers []func(Envelope)
+}
+
+func NewMemoryBackplane() *MemoryBackplane {
+	return &MemoryBackplane{}
+}
+
+func (b *MemoryBackplane) Publish(ctx context.Context, e Envelope) error {
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, deliver := range subscribers {
+		deliver(e)
+	}
+	return nil
+}
+
+func (b *MemoryBackplane) Subscribe(deliver func(Envelope)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, deliver)
+}
@@ -0,0 +1,167 @@
+package websocket
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	// hubChannel is the channel every replica's hub listens on.
+	hubChannel = "rebid_hub"
+	// maxNotifyPayload keeps clear of Postgres' 8000-byte limit on a
+	// NOTIFY payload. Larger messages are stored in hub_messages and only
+	// their id is sent.
+	maxNotifyPayload = 7900
+	// hubMessageTTL is how long a stored message is kept for listeners
+	// to fetch.
+	hubMessageTTL = 5 * time.Minute
+	// listenerPing is how often an idle listener checks its connection.
+	listenerPing = 90 * time.Second
+)
+
+// PostgresBackplane carries hub messages between replicas with
+// LISTEN/NOTIFY on the database they already share. A notification is only
+// sent once the statement publishing it commits, and reaches every listener
+// in the order it was sent. Messages sent while a listener is reconnecting
+// are lost to it.
+type PostgresBackplane struct {
+	db          *sql.DB
+	listener    *pq.Listener
+	mu          sync.RWMutex
+	subscribers []func(Envelope)
+}
+
+// postgresEnvelope is an Envelope as sent over NOTIFY. Ref replaces Message
+// when the message is too large to send inline.
+type postgresEnvelope struct {
+	Envelope
+	Ref *uuid.UUID `json:"ref,omitempty"`
+}
+
+// NewPostgresBackplane starts listening for hub messages on its own
+// connection, opened from dsn, and publishes them through db.
+func NewPostgresBackplane(db *sql.DB, dsn string) (*PostgresBackplane, error) {
+	listener := pq.NewListener(dsn, 100*time.Millisecond, 10*time.Second, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("hub backplane: %v", err)
+		}
+	})
+	if err := listener.Listen(hubChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", hubChannel, err)
+	}
+
+	b := &PostgresBackplane{
+		db:       db,
+		listener: listener,
+	}
+	go b.listen()
+	return b, nil
+}
+
+func (b *PostgresBackplane) Publish(ctx context.Context, e Envelope) error {
+	payload, err := json.Marshal(postgresEnvelope{Envelope: e})
+	if err != nil {
+		return fmt.Errorf("failed to marshal hub message: %w", err)
+	}
+
+	if len(payload) > maxNotifyPayload {
+		ref := uuid.New()
+		query := `INSERT INTO hub_messages (id, payload, created_at) VALUES ($1, $2, NOW())`
+		if _, err := b.db.ExecContext(ctx, query, ref, string(payload)); err != nil {
+			return fmt.Errorf("failed to store hub message: %w", err)
+		}
+		payload, err = json.Marshal(postgresEnvelope{Envelope: Envelope{Room: e.Room, ID: e.ID}, Ref: &ref})
+		if err != nil {
+			return fmt.Errorf("failed to marshal hub message: %w", err)
+		}
+	}
+
+	if _, err := b.db.ExecContext(ctx, `SELECT pg_notify($1, $2)`, hubChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify hub message: %w", err)
+	}
+	return nil
+}
+
+func (b *PostgresBackplane) Subscribe(deliver func(Envelope)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscribers = append(b.subscribers, deliver)
+}
+
+// Close stops listening. Messages published afterwards are not delivered to
+// this backplane's subscribers.
+func (b *PostgresBackplane) Close() error {
+	return b.listener.Close()
+}
+
+func (b *PostgresBackplane) listen() {
+	ticker := time.NewTicker(listenerPing)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case n, ok := <-b.listener.Notify:
+			if !ok {
+				return
+			}
+			if n == nil {
+				// The listener reconnected; anything sent while it was
+				// down is gone.
+				log.Printf("hub backplane: reconnected, messages sent meanwhile were missed")
+				continue
+			}
+			if err := b.receive(n.Extra); err != nil {
+				log.Printf("hub backplane: %v", err)
+			}
+		case <-ticker.C:
+			go b.listener.Ping()
+			b.prune()
+		}
+	}
+}
+
+func (b *PostgresBackplane) receive(payload string) error {
+	ctx := context.Background()
+
+	var e postgresEnvelope
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		return fmt.Errorf("failed to unmarshal hub message: %w", err)
+	}
+	if e.Ref != nil {
+		var stored string
+		err := b.db.QueryRowContext(ctx, `SELECT payload FROM hub_messages WHERE id = $1`, *e.Ref).Scan(&stored)
+		if err != nil {
+			return fmt.Errorf("failed to load hub message %s: %w", *e.Ref, err)
+		}
+		if err := json.Unmarshal([]byte(stored), &e); err != nil {
+			return fmt.Errorf("failed to unmarshal hub message %s: %w", *e.Ref, err)
+		}
+	}
+
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, deliver := range subscribers {
+		deliver(e.Envelope)
+	}
+	return nil
+}
+
+// prune drops stored messages every listener has had time to fetch.
+func (b *PostgresBackplane) prune() {
+	query := `DELETE FROM hub_messages WHERE created_at < NOW() - make_interval(secs => $1)`
+	if _, err := b.db.Exec(query, hubMessageTTL.Seconds()); err != nil {
+		log.Printf("hub backplane: failed to prune hub messages: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/repositories"
+	"rebid/pkg"
+
+	"github.com/google/uuid"
+)
+
+// HandleEventWS subscribes a client to every lot of an event over one
+// connection. It receives the event with all of its lots on connect, then
+// the same auction payloads a lot's own subscribers get; auction.id, or
+// auction_id on a new_bid, tells the lots apart. A resync sends the event
+// again.
+func HandleEventWS(hub *Hub, cfg *config.Config, eventRepo *repositories.AuctionEventRepository, auctionRepo *repositories.AuctionRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		eventIDStr := r.PathValue("id")
+		if eventIDStr == "" {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("event id required"))
+			return
+		}
+		eventID, err := uuid.Parse(eventIDStr)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusBadRequest, pkg.ErrorResponse("invalid event id"))
+			return
+		}
+
+		if _, ok := authenticate(w, r, cfg); !ok {
+			return
+		}
+
+		ctx := r.Context()
+		event, err := eventRepo.GetByID(ctx, eventID)
+		if err != nil {
+			pkg.JSONResponse(w, http.StatusNotFound, pkg.ErrorResponse("event not found"))
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("ws upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		client := &Client{
+			EventID: eventID,
+			Send:    make(chan []byte, 256),
+		}
+		hub.RegisterEvent(eventID, client)
+		defer hub.UnregisterEvent(eventID, client)
+
+		b, err := eventSnapshot(ctx, eventRepo, auctionRepo, event, ChangeConnect)
+		if err != nil {
+			log.Printf("ws event %s: %v", eventID, err)
+			return
+		}
+		if err := write(conn, b); err != nil {
+			return
+		}
+
+		serve(conn, client, func(m ClientMessage) {
+			if m.Action != ActionResync {
+				return
+			}
+			b, err := eventSnapshot(ctx, eventRepo, auctionRepo, event, ChangeResync)
+			if err != nil {
+				log.Printf("ws event %s: %v", eventID, err)
+				return
+			}
+			if !client.push(b) {
+				hub.dropSlow(RoomEvent, hub.events, eventID, client)
+			}
+		})
+	}
+}
+
+// eventSnapshot returns the event with all of its lots as sent on connect
+// and on resync. Each lot carries its own seq.
+func eventSnapshot(ctx context.Context, eventRepo *repositories.AuctionEventRepository, auctionRepo *repositories.AuctionRepository, event *models.AuctionEvent, change string) ([]byte, error) {
+	status, _ := eventRepo.GetStatus(ctx, event.ID)
+	lots, err := auctionRepo.GetAll(ctx, &dto.FilterAuction{EventID: &event.ID})
+	if err != nil {
+		lots = []dto.ResponseAuction{}
+	}
+
+	return json.Marshal(EventSubscribedPayload{
+		Event:        "event",
+		Change:       change,
+		AuctionEvent: *dto.NewResponseAuctionEvent(event, status, lots),
+	})
+}
@@ -1,13 +1,17 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"rebid/internal/config"
 	"rebid/internal/dto"
+	"rebid/internal/models"
 	"rebid/internal/repositories"
 	"rebid/pkg"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
@@ -21,7 +25,10 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-func HandleAuctionWS(hub *Hub, cfg *config.Config, auctionRepo *repositories.AuctionRepository, bidRepo *repositories.BidRepository) http.HandlerFunc {
+// HandleAuctionWS subscribes a client to one auction. It receives the
+// auction's full state on connect and whenever it asks to resync, and each
+// change in between.
+func HandleAuctionWS(hub *Hub, cfg *config.Config, auctionRepo *repositories.AuctionRepository) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		auctionIDStr := r.PathValue("id")
 		if auctionIDStr == "" {
@@ -34,29 +41,7 @@ func HandleAuctionWS(hub *Hub, cfg *config.Config, auctionRepo *repositories.Auc
 			return
 		}
 
-		// token := r.URL.Query().Get("token")
-		// if token == "" {
-		// 	pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("token required"))
-		// 	return
-		// }
-		var tokenStr string
-		if c, err := r.Cookie(cfg.CookieName); err == nil {
-			tokenStr = c.Value
-		}
-
-		if tokenStr == "" {
-			pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("token required"))
-			return
-		}
-
-		claims, err := pkg.ParseToken(tokenStr, cfg.JWTSecret)
-		if err != nil {
-			pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("invalid token"))
-			return
-		}
-		_, err = uuid.Parse(claims.UserID)
-		if err != nil {
-			pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("invalid user"))
+		if _, ok := authenticate(w, r, cfg); !ok {
 			return
 		}
 
@@ -75,44 +60,165 @@ func HandleAuctionWS(hub *Hub, cfg *config.Config, auctionRepo *repositories.Auc
 		defer hub.Unregister(auctionID, client)
 
 		ctx := r.Context()
-		var response dto.ResponseAuction
-		if auctionRepo != nil {
-			if res, err := auctionRepo.GetByID(ctx, auctionID); err == nil {
-				response = *res
-			}
+		b, err := auctionSnapshot(ctx, cfg, auctionRepo, auctionID, ChangeConnect)
+		if err != nil {
+			log.Printf("ws auction %s: %v", auctionID, err)
+			return
+		}
+		if err := write(conn, b); err != nil {
+			return
 		}
 
-		var bidsWithUser []dto.ResponseBidWithUser
-		if bidRepo != nil {
-			if bids, err := bidRepo.GetListBidByAuctionID(ctx, auctionID); err == nil {
-				bidsWithUser = bids
+		serve(conn, client, func(m ClientMessage) {
+			if m.Action != ActionResync {
+				return
 			}
-		}
+			b, err := auctionSnapshot(ctx, cfg, auctionRepo, auctionID, ChangeResync)
+			if err != nil {
+				log.Printf("ws auction %s: %v", auctionID, err)
+				return
+			}
+			if !client.push(b) {
+				hub.dropSlow(RoomAuction, hub.auctions, auctionID, client)
+			}
+		})
+	}
+}
 
-		msg := SubscribedPayload{
-			Event:           "auction",
-			Change:          ChangeConnect,
-			Auction:         response,
-			CurrentPrice:    response.CurrentPrice,
-			CurrentBidderID: response.CurrentBidderID,
-			Bids:            bidsWithUser,
+// auctionSnapshot returns the full state of an auction as sent on connect and
+// on resync, tagged with the seq of the auction's last event.
+func auctionSnapshot(ctx context.Context, cfg *config.Config, auctionRepo *repositories.AuctionRepository, auctionID uuid.UUID, change string) ([]byte, error) {
+	auction, bids, err := auctionRepo.GetWithBids(ctx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("get auction: %w", err)
+	}
+	auction.ResolveImageURLs(cfg.BaseURL)
+	return json.Marshal(newSubscribedPayload(auction, bids, change))
+}
+
+// newSubscribedPayload wraps auction with its bids, sealed while the auction
+// hides them. The bids must have been read along with auction, as its seq
+// is sent as theirs.
+func newSubscribedPayload(auction *dto.ResponseAuction, bids []dto.ResponseBidWithUser, change string) *SubscribedPayload {
+	if models.AuctionType(auction.AuctionType).HidesBids(models.AuctionStatus(auction.Status)) {
+		bids = dto.SealBids(bids)
+	}
+
+	return &SubscribedPayload{
+		Seq:             auction.Seq,
+		Event:           "auction",
+		Change:          change,
+		Auction:         *auction,
+		CurrentPrice:    auction.CurrentPrice,
+		CurrentBidderID: auction.CurrentBidderID,
+		MinNextBid:      auction.MinNextBid,
+		ReserveMet:      auction.ReserveMet,
+		BidCount:        len(bids),
+		Bids:            bids,
+	}
+}
+
+// authenticate checks the session cookie and returns the user it belongs to,
+// writing the error response and returning false when the request may not
+// subscribe.
+func authenticate(w http.ResponseWriter, r *http.Request, cfg *config.Config) (uuid.UUID, bool) {
+	// token := r.URL.Query().Get("token")
+	// if token == "" {
+	// 	pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("token required"))
+	// 	return
+	// }
+	var tokenStr string
+	if c, err := r.Cookie(cfg.CookieName); err == nil {
+		tokenStr = c.Value
+	}
+
+	if tokenStr == "" {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("token required"))
+		return uuid.Nil, false
+	}
+
+	claims, err := pkg.ParseToken(tokenStr, cfg.JWTSecret)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("invalid token"))
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		pkg.JSONResponse(w, http.StatusUnauthorized, pkg.ErrorResponse("invalid user"))
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// Connection timing. A client that has not answered a ping within pongWait
+// is dropped, and so is one that does not take a write within writeWait.
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 4096
+)
+
+// CloseSlowConsumer is the close code sent to a client disconnected for
+// falling too far behind. It may reconnect and start from a fresh snapshot.
+const CloseSlowConsumer = 4008
+
+// write sends one message, giving up after writeWait.
+func write(conn *websocket.Conn, b []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteMessage(websocket.TextMessage, b)
+}
+
+// serve pumps hub messages to the connection until the client disconnects,
+// passing each message the client sends to handle. handle may be nil.
+func serve(conn *websocket.Conn, client *Client, handle func(ClientMessage)) {
+	go pump(conn, client)
+
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, b, err := conn.ReadMessage()
+		if err != nil {
+			break
 		}
-		b, _ := json.Marshal(msg)
-		if err := conn.WriteMessage(websocket.TextMessage, b); err != nil {
-			return
+		var m ClientMessage
+		if handle == nil || json.Unmarshal(b, &m) != nil {
+			continue
 		}
+		handle(m)
+	}
+}
 
-		go func() {
-			for b := range client.Send {
-				if err := conn.WriteMessage(websocket.TextMessage, b); err != nil {
-					return
-				}
-			}
-		}()
+// pump writes the client's messages and pings it every pingPeriod. It closes
+// the connection when a write fails or the hub cuts the client off, which
+// ends serve's read loop too.
+func pump(conn *websocket.Conn, client *Client) {
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	defer conn.Close()
 
-		for {
-			if _, _, err := conn.ReadMessage(); err != nil {
-				break
+	for {
+		select {
+		case b, ok := <-client.Send:
+			if client.isSlow() {
+				msg := websocket.FormatCloseMessage(CloseSlowConsumer, "slow consumer: too many messages pending")
+				conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(writeWait))
+				return
+			}
+			if !ok {
+				return
+			}
+			if err := write(conn, b); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
 			}
 		}
 	}
@@ -1,65 +1,334 @@
 package websocket
 
 import (
+	"context"
+	"log"
+	"rebid/internal/dto"
+	"sort"
 	"sync"
 
 	"github.com/google/uuid"
 )
 
+// Client is one websocket subscriber. It belongs to either an auction room
+// (AuctionID), an event room (EventID) or its user's own room (UserID).
 type Client struct {
 	AuctionID uuid.UUID
+	EventID   uuid.UUID
+	UserID    uuid.UUID
 	Send      chan []byte
+
+	mu     sync.Mutex
+	closed bool
+	slow   bool
+}
+
+// push queues a message for the client alone, reporting false when its
+// buffer is full.
+func (c *Client) push(message []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return true
+	}
+	select {
+	case c.Send <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// cutOff marks the client as too slow to keep and returns how many messages
+// were still queued for it. They are never sent.
+func (c *Client) cutOff() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.slow = true
+	return len(c.Send)
+}
+
+func (c *Client) isSlow() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.slow
 }
 
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.Send)
+	}
+}
+
+type rooms map[uuid.UUID]map[*Client]struct{}
+
+type roomKey struct {
+	room string
+	id   uuid.UUID
+}
+
+type roomCounters struct {
+	dropped      int64
+	disconnected int64
+}
+
+// Hub tracks this process's subscribers. Broadcasts go out through the
+// backplane and reach the subscribers of every hub sharing it. A subscriber
+// whose buffer fills up is disconnected rather than silently skipped, and
+// the messages it missed are counted per room. A room's counts are folded
+// into the hub's totals when its last subscriber leaves.
 type Hub struct {
-	mu       sync.RWMutex
-	auctions map[uuid.UUID]map[*Client]struct{}
+	mu        sync.RWMutex
+	auctions  rooms
+	events    rooms
+	users     rooms
+	backplane Backplane
+
+	statsMu sync.Mutex
+	stats   map[roomKey]*roomCounters
+	retired roomCounters
 }
 
-func NewHub() *Hub {
-	return &Hub{
-		auctions: make(map[uuid.UUID]map[*Client]struct{}),
+func NewHub(backplane Backplane) *Hub {
+	h := &Hub{
+		auctions:  make(rooms),
+		events:    make(rooms),
+		users:     make(rooms),
+		backplane: backplane,
+		stats:     make(map[roomKey]*roomCounters),
 	}
+	backplane.Subscribe(h.deliver)
+	return h
 }
 
 func (h *Hub) Register(auctionID uuid.UUID, client *Client) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if h.auctions[auctionID] == nil {
-		h.auctions[auctionID] = make(map[*Client]struct{})
-	}
-
-	h.auctions[auctionID][client] = struct{}{}
+	h.auctions.add(auctionID, client)
 }
 
 func (h *Hub) Unregister(auctionID uuid.UUID, client *Client) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if m, ok := h.auctions[auctionID]; ok {
-		delete(m, client)
-		close(client.Send)
-		if len(m) == 0 {
-			delete(h.auctions, auctionID)
-		}
+	h.auctions.remove(auctionID, client)
+	h.retire(RoomAuction, h.auctions, auctionID)
+}
+
+func (h *Hub) RegisterEvent(eventID uuid.UUID, client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events.add(eventID, client)
+}
+
+func (h *Hub) UnregisterEvent(eventID uuid.UUID, client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.events.remove(eventID, client)
+	h.retire(RoomEvent, h.events, eventID)
+}
+
+// RegisterUser subscribes a client to everything sent to userID, whatever
+// auction it is about. A user may have several connections open.
+func (h *Hub) RegisterUser(userID uuid.UUID, client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.users.add(userID, client)
+}
+
+func (h *Hub) UnregisterUser(userID uuid.UUID, client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.users.remove(userID, client)
+	h.retire(RoomUser, h.users, userID)
+}
+
+func (h *Hub) BroadcastToAuction(ctx context.Context, auctionID uuid.UUID, message []byte) error {
+	return h.backplane.Publish(ctx, Envelope{Room: RoomAuction, ID: auctionID, Message: message})
+}
+
+func (h *Hub) BroadcastToEvent(ctx context.Context, eventID uuid.UUID, message []byte) error {
+	return h.backplane.Publish(ctx, Envelope{Room: RoomEvent, ID: eventID, Message: message})
+}
+
+func (h *Hub) SendToUser(ctx context.Context, userID uuid.UUID, message []byte) error {
+	return h.backplane.Publish(ctx, Envelope{Room: RoomUser, ID: userID, Message: message})
+}
+
+// BroadcastToLot sends an auction update to the auction's own subscribers
+// and, when the auction is a lot, to everyone following its event.
+func (h *Hub) BroadcastToLot(ctx context.Context, auctionID uuid.UUID, eventID *uuid.UUID, message []byte) error {
+	if err := h.BroadcastToAuction(ctx, auctionID, message); err != nil {
+		return err
+	}
+	if eventID != nil {
+		return h.BroadcastToEvent(ctx, *eventID, message)
 	}
+	return nil
 }
 
-func (h *Hub) BroadcastToAuction(auctionID uuid.UUID, message []byte) {
+// deliver hands a message from the backplane to this hub's own subscribers.
+func (h *Hub) deliver(e Envelope) {
+	switch e.Room {
+	case RoomAuction:
+		h.broadcast(RoomAuction, h.auctions, e.ID, e.Message)
+	case RoomEvent:
+		h.broadcast(RoomEvent, h.events, e.ID, e.Message)
+	case RoomUser:
+		h.broadcast(RoomUser, h.users, e.ID, e.Message)
+	}
+}
+
+func (h *Hub) broadcast(room string, r rooms, id uuid.UUID, message []byte) {
 	h.mu.RLock()
-	clients := make([]*Client, 0, len(h.auctions[auctionID]))
+	clients := make([]*Client, 0, len(r[id]))
 
-	for c := range h.auctions[auctionID] {
+	for c := range r[id] {
 		clients = append(clients, c)
 	}
 
 	h.mu.RUnlock()
 
 	for _, c := range clients {
-		select {
-		case c.Send <- message:
-		default:
+		if !c.push(message) {
+			h.dropSlow(room, r, id, c)
+		}
+	}
+}
+
+// dropSlow disconnects a client that fell a full buffer behind, counting the
+// message it could not take and every one still queued for it.
+func (h *Hub) dropSlow(room string, r rooms, id uuid.UUID, client *Client) {
+	h.mu.Lock()
+	pending := client.cutOff()
+	if !r.remove(id, client) {
+		h.mu.Unlock()
+		return
+	}
+
+	h.statsMu.Lock()
+	counters := h.stats[roomKey{room, id}]
+	if counters == nil {
+		counters = &roomCounters{}
+		h.stats[roomKey{room, id}] = counters
+	}
+	counters.dropped += int64(pending + 1)
+	counters.disconnected++
+	h.statsMu.Unlock()
+
+	h.retire(room, r, id)
+	h.mu.Unlock()
+
+	log.Printf("ws %s %s: disconnected a slow consumer, %d messages dropped", room, id, pending+1)
+}
+
+// retire folds the counts of room id into the hub's totals once its last
+// client has left, so closed rooms are not kept forever. The caller holds
+// h.mu.
+func (h *Hub) retire(room string, r rooms, id uuid.UUID) {
+	if _, open := r[id]; open {
+		return
+	}
+
+	h.statsMu.Lock()
+	defer h.statsMu.Unlock()
+
+	key := roomKey{room, id}
+	if counters, ok := h.stats[key]; ok {
+		h.retired.dropped += counters.dropped
+		h.retired.disconnected += counters.disconnected
+		delete(h.stats, key)
+	}
+}
+
+// Metrics returns, for every open room of this hub, how many clients it has
+// and how many messages and clients it lost to slow consumers, the worst
+// first. The totals also count rooms that have since closed.
+func (h *Hub) Metrics() *dto.ResponseHubMetrics {
+	metrics := make(map[roomKey]*dto.ResponseHubRoom)
+	get := func(key roomKey) *dto.ResponseHubRoom {
+		m, ok := metrics[key]
+		if !ok {
+			m = &dto.ResponseHubRoom{Room: key.room, ID: key.id}
+			metrics[key] = m
+		}
+		return m
+	}
+
+	h.mu.RLock()
+	for room, r := range map[string]rooms{RoomAuction: h.auctions, RoomEvent: h.events, RoomUser: h.users} {
+		for id, clients := range r {
+			get(roomKey{room, id}).Clients = len(clients)
+		}
+	}
+	h.mu.RUnlock()
+
+	response := &dto.ResponseHubMetrics{}
+	h.statsMu.Lock()
+	for key, counters := range h.stats {
+		m := get(key)
+		m.Dropped = counters.dropped
+		m.Disconnected = counters.disconnected
+	}
+	response.Dropped = h.retired.dropped
+	response.Disconnected = h.retired.disconnected
+	h.statsMu.Unlock()
+
+	response.Rooms = make([]dto.ResponseHubRoom, 0, len(metrics))
+	for _, m := range metrics {
+		response.Rooms = append(response.Rooms, *m)
+		response.Clients += m.Clients
+		response.Dropped += m.Dropped
+		response.Disconnected += m.Disconnected
+	}
+	sort.Slice(response.Rooms, func(i, j int) bool {
+		a, b := response.Rooms[i], response.Rooms[j]
+		if a.Dropped != b.Dropped {
+			return a.Dropped > b.Dropped
+		}
+		if a.Room != b.Room {
+			return a.Room < b.Room
 		}
+		return a.ID.String() < b.ID.String()
+	})
+	return response
+}
+
+func (r rooms) add(id uuid.UUID, client *Client) {
+	if r[id] == nil {
+		r[id] = make(map[*Client]struct{})
+	}
+
+	r[id][client] = struct{}{}
+}
+
+// remove takes client out of room id and closes it, reporting false when it
+// had already left.
+func (r rooms) remove(id uuid.UUID, client *Client) bool {
+	m, ok := r[id]
+	if !ok {
+		return false
+	}
+	if _, ok := m[client]; !ok {
+		return false
+	}
+
+	delete(m, client)
+	client.close()
+	if len(m) == 0 {
+		delete(r, id)
 	}
+	return true
 }
@@ -0,0 +1,99 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/events"
+	"rebid/internal/models"
+	"rebid/internal/repositories"
+)
+
+// HubSink publishes domain events to the auction's subscribers, and its
+// event's when it is a lot. A new_bid event goes out as just the bids it
+// placed; anything else as the auction's state at the time of publishing,
+// tagged with the seq of the last event that state reflects, which may be
+// past the event's own. An event that is published twice repeats its ID, so
+// clients can drop it.
+type HubSink struct {
+	hub         *Hub
+	cfg         *config.Config
+	auctionRepo *repositories.AuctionRepository
+	bidRepo     *repositories.BidRepository
+}
+
+func NewHubSink(hub *Hub, cfg *config.Config, auctionRepo *repositories.AuctionRepository, bidRepo *repositories.BidRepository) *HubSink {
+	return &HubSink{
+		hub:         hub,
+		cfg:         cfg,
+		auctionRepo: auctionRepo,
+		bidRepo:     bidRepo,
+	}
+}
+
+func (s *HubSink) Publish(ctx context.Context, e events.Event) error {
+	// Every bid on a multi-quantity auction can move units between standing
+	// bids, so those subscribers keep getting the whole list.
+	if e.Change == ChangeNewBid && len(e.BidIDs) > 0 {
+		auction, err := s.auctionRepo.GetByID(ctx, e.AuctionID)
+		if err != nil {
+			return fmt.Errorf("get auction: %w", err)
+		}
+		if auction.Quantity <= 1 {
+			msg, err := s.newBidPayload(ctx, e, auction)
+			if err != nil {
+				return err
+			}
+			return s.broadcast(ctx, auction, msg)
+		}
+	}
+
+	auction, bids, err := s.auctionRepo.GetWithBids(ctx, e.AuctionID)
+	if err != nil {
+		return fmt.Errorf("get auction: %w", err)
+	}
+	auction.ResolveImageURLs(s.cfg.BaseURL)
+
+	msg := newSubscribedPayload(auction, bids, e.Change)
+	msg.ID = &e.ID
+	return s.broadcast(ctx, auction, msg)
+}
+
+func (s *HubSink) broadcast(ctx context.Context, auction *dto.ResponseAuction, msg any) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("marshal payload: %w", err)
+	}
+	return s.hub.BroadcastToLot(ctx, auction.ID, auction.EventID, b)
+}
+
+func (s *HubSink) newBidPayload(ctx context.Context, e events.Event, auction *dto.ResponseAuction) (*NewBidPayload, error) {
+	bids, err := s.bidRepo.GetListBidByIDs(ctx, e.AuctionID, e.BidIDs)
+	if err != nil {
+		return nil, fmt.Errorf("get bids: %w", err)
+	}
+	if models.AuctionType(auction.AuctionType).HidesBids(models.AuctionStatus(auction.Status)) {
+		bids = dto.SealBids(bids)
+	}
+	count, err := s.bidRepo.CountByAuctionID(ctx, e.AuctionID)
+	if err != nil {
+		return nil, fmt.Errorf("count bids: %w", err)
+	}
+
+	return &NewBidPayload{
+		ID:              &e.ID,
+		Seq:             e.AuctionSeq,
+		Event:           "auction",
+		Change:          e.Change,
+		AuctionID:       e.AuctionID,
+		CurrentPrice:    auction.CurrentPrice,
+		CurrentBidderID: auction.CurrentBidderID,
+		MinNextBid:      auction.MinNextBid,
+		ReserveMet:      auction.ReserveMet,
+		BuyNowPrice:     auction.BuyNowPrice,
+		BidCount:        count,
+		Bids:            bids,
+	}, nil
+}
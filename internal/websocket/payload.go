@@ -9,17 +9,75 @@ import (
 const ChangeConnect = "connect"
 const ChangeNewBid = "new_bid"
 const ChangeAuctionEnded = "auction_ended"
+const ChangeAuctionExtended = "auction_extended"
+const ChangeAuctionStarted = "auction_started"
+const ChangeAuctionCancelled = "auction_cancelled"
+const ChangePriceTick = "price_tick"
+const ChangeBidVoided = "bid_voided"
+const ChangeNewNotification = "new_notification"
+const ChangeResync = "resync"
 
+// ActionResync asks for a fresh snapshot after a client missed a message.
+const ActionResync = "resync"
+
+// ClientMessage is a request a subscriber sends over its connection.
+type ClientMessage struct {
+	Action string `json:"action"`
+}
+
+// NewBidPayload reports the bids a new_bid event placed and the auction's
+// price after them, for subscribers that already hold the auction's state.
+// A null buy_now_price means the bids withdrew buy-it-now.
 type NewBidPayload struct {
-	Event string          `json:"event"`
-	Bid   dto.ResponseBid `json:"bid"`
+	ID              *uuid.UUID                `json:"id,omitempty"`
+	Seq             int64                     `json:"seq"`
+	Event           string                    `json:"event"`
+	Change          string                    `json:"change"`
+	AuctionID       uuid.UUID                 `json:"auction_id"`
+	CurrentPrice    float64                   `json:"current_price"`
+	CurrentBidderID *uuid.UUID                `json:"current_bidder_id"`
+	MinNextBid      float64                   `json:"min_next_bid"`
+	ReserveMet      bool                      `json:"reserve_met"`
+	BuyNowPrice     *float64                  `json:"buy_now_price"`
+	BidCount        int                       `json:"bid_count"`
+	Bids            []dto.ResponseBidWithUser `json:"bids"`
 }
 
+// SubscribedPayload is the full state of an auction. Pushed changes carry
+// the ID of the domain event behind them, which repeats if the event is
+// delivered twice.
+//
+// Every auction message carries seq, the number of the auction's last event
+// it reflects. A client applies a message whose seq is one past the last it
+// applied and drops older ones. A full state covers every event up to its
+// seq, so it is applied across a gap too. On any other gap the client sends
+// {"action":"resync"}, ignores further messages until the resync snapshot
+// arrives and carries on from that snapshot's seq.
 type SubscribedPayload struct {
+	ID              *uuid.UUID                `json:"id,omitempty"`
+	Seq             int64                     `json:"seq"`
 	Event           string                    `json:"event"`
 	Change          string                    `json:"change"`
 	Auction         dto.ResponseAuction       `json:"auction"`
 	CurrentPrice    float64                   `json:"current_price"`
 	CurrentBidderID *uuid.UUID                `json:"current_bidder_id"`
+	MinNextBid      float64                   `json:"min_next_bid"`
+	ReserveMet      bool                      `json:"reserve_met"`
+	BidCount        int                       `json:"bid_count"`
 	Bids            []dto.ResponseBidWithUser `json:"bids"`
 }
+
+type EventSubscribedPayload struct {
+	Event        string                   `json:"event"`
+	Change       string                   `json:"change"`
+	AuctionEvent dto.ResponseAuctionEvent `json:"auction_event"`
+}
+
+// InboxPayload is sent on a user's own channel: their unread notifications
+// on connect, then each new notification as it arrives.
+type InboxPayload struct {
+	Event         string                     `json:"event"`
+	Change        string                     `json:"change"`
+	UnreadCount   int                        `json:"unread_count"`
+	Notifications []dto.ResponseNotification `json:"notifications"`
+}
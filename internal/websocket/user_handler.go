@@ -0,0 +1,104 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"rebid/internal/config"
+	"rebid/internal/dto"
+	"rebid/internal/models"
+	"rebid/internal/notify"
+	"rebid/internal/repositories"
+	"time"
+)
+
+// userSnapshotSize is how many unread notifications a user channel sends on
+// connect.
+const userSnapshotSize = 20
+
+// HandleUserWS subscribes the signed-in user to their own notifications,
+// whatever auction they are about. It receives the unread count and latest
+// unread notifications on connect, then each new notification as it is
+// stored.
+func HandleUserWS(hub *Hub, cfg *config.Config, notifyRepo *repositories.NotificationRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := authenticate(w, r, cfg)
+		if !ok {
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Printf("ws upgrade: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		client := &Client{
+			UserID: userID,
+			Send:   make(chan []byte, 256),
+		}
+		hub.RegisterUser(userID, client)
+		defer hub.UnregisterUser(userID, client)
+
+		ctx := r.Context()
+		msg := InboxPayload{
+			Event:         "notification",
+			Change:        ChangeConnect,
+			Notifications: []dto.ResponseNotification{},
+		}
+		if count, err := notifyRepo.CountUnread(ctx, userID); err == nil {
+			msg.UnreadCount = count
+		}
+		unread, err := notifyRepo.ListInbox(ctx, userID, &dto.FilterNotification{Limit: userSnapshotSize, Unread: true})
+		if err == nil {
+			for i := range unread {
+				msg.Notifications = append(msg.Notifications, *dto.NewResponseNotification(&unread[i]))
+			}
+		}
+		b, _ := json.Marshal(msg)
+		if err := write(conn, b); err != nil {
+			return
+		}
+
+		serve(conn, client, nil)
+	}
+}
+
+// UserNotifier pushes notifications to their user's open channels. It is
+// best effort: a user with no channel open reads them from the inbox later.
+type UserNotifier struct {
+	hub  *Hub
+	repo *repositories.NotificationRepository
+}
+
+func NewUserNotifier(hub *Hub, repo *repositories.NotificationRepository) *UserNotifier {
+	return &UserNotifier{hub: hub, repo: repo}
+}
+
+func (n *UserNotifier) Notify(ctx context.Context, msg notify.Message) error {
+	notification := models.Notification{
+		ID:        msg.ID,
+		UserID:    msg.UserID,
+		AuctionID: msg.AuctionID,
+		Kind:      msg.Kind,
+		Subject:   msg.Subject,
+		Body:      msg.Body,
+		CreatedAt: time.Now(),
+	}
+	payload := InboxPayload{
+		Event:         "notification",
+		Change:        ChangeNewNotification,
+		Notifications: []dto.ResponseNotification{*dto.NewResponseNotification(&notification)},
+	}
+	if count, err := n.repo.CountUnread(ctx, msg.UserID); err == nil {
+		payload.UnreadCount = count
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return n.hub.SendToUser(ctx, msg.UserID, b)
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"rebid/internal/services"
+
+	"github.com/google/uuid"
+)
+
+const activatorName = "auction activator"
+
+func StartAuctionActivator(
+	d context.Context,
+	elector *Elector,
+	cronExpr string,
+	auctionSvc *services.AuctionService,
+) {
+	schedule(d, elector, activatorName, cronExpr, func() {
+		RunActivate(context.Background(), auctionSvc)
+	})
+}
+
+// RunActivate is one pass of the activator: it starts every SCHEDULED auction
+// whose start_time has passed, recording auction_started for each of them.
+func RunActivate(ctx context.Context, auctionSvc *services.AuctionService) []uuid.UUID {
+	startedIDs, err := auctionSvc.ActivateScheduledAuctions(ctx)
+	if err != nil {
+		log.Printf("%s: error activating scheduled auctions: %v", activatorName, err)
+		return nil
+	}
+	if len(startedIDs) == 0 {
+		return nil
+	}
+
+	log.Printf("%s: started %d auction(s)", activatorName, len(startedIDs))
+	return startedIDs
+}
@@ -2,96 +2,48 @@ package worker
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"rebid/internal/services"
-	"rebid/internal/websocket"
 
 	"github.com/google/uuid"
-	"github.com/robfig/cron/v3"
 )
 
-const defaultCronExpr = "*/30 * * * * *"
+const closerName = "auction closer"
 
 func StartAuctionCloser(
 	d context.Context,
+	elector *Elector,
 	cronExpr string,
 	auctionSvc *services.AuctionService,
-	bidSvc *services.BidService,
-	hub *websocket.Hub,
 ) {
-	c := cron.New(cron.WithSeconds())
-
-	_, err := c.AddFunc(cronExpr, func() {
-		runClose(auctionSvc, bidSvc, hub)
+	schedule(d, elector, closerName, cronExpr, func() {
+		RunClose(context.Background(), auctionSvc)
 	})
+}
+
+// RunClose is one pass of the closer: it ends every ACTIVE auction past its
+// end_time, recording auction_ended for each of them. Event lots are
+// restaggered first so a lot behind an extended one is pushed back before it
+// can be closed.
+func RunClose(ctx context.Context, auctionSvc *services.AuctionService) []uuid.UUID {
+	staggeredIDs, err := auctionSvc.StaggerEventLots(ctx)
 	if err != nil {
-		log.Printf("auction closer: invalid cron expression %q: %v — falling back to %s", cronExpr, err, defaultCronExpr)
-		c.AddFunc(defaultCronExpr, func() {
-			runClose(auctionSvc, bidSvc, hub)
-		})
+		log.Printf("%s: error staggering event lots: %v", closerName, err)
+		return nil
+	}
+	if len(staggeredIDs) > 0 {
+		log.Printf("%s: pushed back %d event lot(s)", closerName, len(staggeredIDs))
 	}
 
-	c.Start()
-	log.Printf("auction closer: started (cron=%q)", cronExpr)
-
-	go func() {
-		<-d.Done()
-		log.Println("auction closer: shutdown signal received, waiting for running job...")
-		stopCtx := c.Stop()
-		<-stopCtx.Done()
-		log.Println("auction closer: stopped")
-	}()
-}
-
-func runClose(auctionSvc *services.AuctionService, bidSvc *services.BidService, hub *websocket.Hub) {
-	closedIDs, err := auctionSvc.CloseExpiredAuctions(context.Background())
+	closedIDs, err := auctionSvc.CloseExpiredAuctions(ctx)
 	if err != nil {
-		log.Printf("auction closer: error closing expired auctions: %v", err)
-		return
+		log.Printf("%s: error closing expired auctions: %v", closerName, err)
+		return nil
 	}
 	if len(closedIDs) == 0 {
-		return
+		return nil
 	}
 
-	log.Printf("auction closer: closed %d auction(s)", len(closedIDs))
-	broadcastEnded(auctionSvc, bidSvc, hub, closedIDs)
-}
-
-func broadcastEnded(
-	auctionSvc *services.AuctionService,
-	bidSvc *services.BidService,
-	hub *websocket.Hub,
-	ids []uuid.UUID,
-) {
-	ctx := context.Background()
-	for _, id := range ids {
-		auction, err := auctionSvc.GetAuctionByID(ctx, id.String())
-		if err != nil {
-			log.Printf("auction closer: get auction %s: %v", id, err)
-			continue
-		}
-
-		bids, err := bidSvc.GetListBidByAuctionID(ctx, id.String())
-		if err != nil {
-			log.Printf("auction closer: get bids %s: %v", id, err)
-			continue
-		}
-
-		payload := websocket.SubscribedPayload{
-			Event:           "auction",
-			Change:          websocket.ChangeAuctionEnded,
-			Auction:         *auction,
-			CurrentPrice:    auction.CurrentPrice,
-			CurrentBidderID: auction.CurrentBidderID,
-			Bids:            bids,
-		}
-
-		b, err := json.Marshal(payload)
-		if err != nil {
-			log.Printf("auction closer: marshal payload %s: %v", id, err)
-			continue
-		}
-		hub.BroadcastToAuction(id, b)
-	}
+	log.Printf("%s: closed %d auction(s)", closerName, len(closedIDs))
+	return closedIDs
 }
@@ -0,0 +1,43 @@
+package worker
+
+import (
+	"context"
+	"log"
+
+	"github.com/robfig/cron/v3"
+)
+
+const defaultCronExpr = "*/30 * * * * *"
+
+// schedule runs job on cronExpr until d is cancelled, falling back to
+// defaultCronExpr when the expression does not parse. With an elector the
+// job only runs on the node leading it; a nil elector runs it everywhere.
+func schedule(d context.Context, elector *Elector, name, cronExpr string, job func()) {
+	c := cron.New(cron.WithSeconds())
+
+	run := job
+	if elector != nil {
+		run = func() {
+			if elector.Lead(d, name) {
+				job()
+			}
+		}
+	}
+
+	_, err := c.AddFunc(cronExpr, run)
+	if err != nil {
+		log.Printf("%s: invalid cron expression %q: %v — falling back to %s", name, cronExpr, err, defaultCronExpr)
+		c.AddFunc(defaultCronExpr, run)
+	}
+
+	c.Start()
+	log.Printf("%s: started (cron=%q)", name, cronExpr)
+
+	go func() {
+		<-d.Done()
+		log.Printf("%s: shutdown signal received, waiting for running job...", name)
+		stopCtx := c.Stop()
+		<-stopCtx.Done()
+		log.Printf("%s: stopped", name)
+	}()
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"rebid/internal/services"
+
+	"github.com/google/uuid"
+)
+
+const dutchTickerName = "dutch price ticker"
+
+func StartDutchPriceTicker(
+	d context.Context,
+	elector *Elector,
+	cronExpr string,
+	auctionSvc *services.AuctionService,
+) {
+	schedule(d, elector, dutchTickerName, cronExpr, func() {
+		RunDutchTick(context.Background(), auctionSvc)
+	})
+}
+
+// RunDutchTick is one pass of the ticker: it lowers the price of every Dutch
+// auction that is due for a drop, recording price_tick for each of them.
+func RunDutchTick(ctx context.Context, auctionSvc *services.AuctionService) []uuid.UUID {
+	droppedIDs, err := auctionSvc.DropDutchPrices(ctx)
+	if err != nil {
+		log.Printf("%s: error dropping dutch prices: %v", dutchTickerName, err)
+		return nil
+	}
+	if len(droppedIDs) == 0 {
+		return nil
+	}
+
+	log.Printf("%s: dropped price on %d auction(s)", dutchTickerName, len(droppedIDs))
+	return droppedIDs
+}
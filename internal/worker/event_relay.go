@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"rebid/internal/services"
+	"time"
+)
+
+const relayName = "event relay"
+
+// StartEventRelay publishes queued domain events every interval until d is
+// cancelled. It runs on a plain ticker like the notification dispatcher, but
+// far more often, as subscribers wait on it to see every bid. Only the node
+// leading the relay publishes, so each auction's events go out in order.
+func StartEventRelay(
+	d context.Context,
+	elector *Elector,
+	interval time.Duration,
+	eventSvc *services.DomainEventService,
+) {
+	log.Printf("%s: started (interval=%s)", relayName, interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.Done():
+				log.Printf("%s: stopped", relayName)
+				return
+			case <-ticker.C:
+				if elector.Lead(d, relayName) {
+					RunRelay(d, eventSvc)
+				}
+			}
+		}
+	}()
+}
+
+// RunRelay is one pass of the relay: it publishes every domain event that
+// is due, scheduling retries for the deliveries that fail.
+func RunRelay(ctx context.Context, eventSvc *services.DomainEventService) int {
+	published, err := eventSvc.Relay(ctx)
+	if err != nil {
+		log.Printf("%s: error relaying events: %v", relayName, err)
+	}
+	return published
+}
@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"rebid/internal/repositories"
+	"sync"
+)
+
+// Elector decides which node runs each scheduled job. A node leads a job
+// while it holds the job's Postgres advisory lock on a connection of its
+// own; when the node dies the connection, and the lock with it, goes away
+// and the next node whose schedule fires takes over.
+type Elector struct {
+	db     *sql.DB
+	repo   *repositories.WorkerLeaseRepository
+	nodeID string
+
+	mu   sync.Mutex
+	conn *sql.Conn
+	held map[string]bool
+}
+
+func NewElector(db *sql.DB, repo *repositories.WorkerLeaseRepository, nodeID string) *Elector {
+	return &Elector{
+		db:     db,
+		repo:   repo,
+		nodeID: nodeID,
+		held:   make(map[string]bool),
+	}
+}
+
+// Lead reports whether this node should run job now, taking the lead when
+// no other node holds it.
+func (e *Elector) Lead(ctx context.Context, job string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		conn, err := e.db.Conn(ctx)
+		if err != nil {
+			log.Printf("%s: leader election: %v", job, err)
+			return false
+		}
+		e.conn = conn
+	}
+
+	if e.held[job] {
+		if err := e.repo.Heartbeat(ctx, e.conn, job, e.nodeID); err != nil {
+			log.Printf("%s: leader election: lost the lead: %v", job, err)
+			e.reset()
+			return false
+		}
+		return true
+	}
+
+	key := lockKey(job)
+	locked, err := e.repo.TryLock(ctx, e.conn, key)
+	if err != nil {
+		log.Printf("%s: leader election: %v", job, err)
+		e.reset()
+		return false
+	}
+	if !locked {
+		return false
+	}
+
+	e.held[job] = true
+	if err := e.repo.Acquire(ctx, e.conn, job, key, e.nodeID); err != nil {
+		log.Printf("%s: leader election: %v", job, err)
+	}
+	log.Printf("%s: node %s took the lead", job, e.nodeID)
+	return true
+}
+
+// Close gives up every lead this node holds.
+func (e *Elector) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+	err := e.conn.Close()
+	e.conn = nil
+	e.held = make(map[string]bool)
+	return err
+}
+
+// reset drops a connection that failed. Any lock it held is released with
+// it, so every lead is given up and has to be won again.
+func (e *Elector) reset() {
+	e.conn.Close()
+	e.conn = nil
+	e.held = make(map[string]bool)
+}
+
+// lockKey turns a job name into its advisory lock key. Keys are kept
+// non-negative so they read back the same from pg_locks.
+func lockKey(job string) int64 {
+	h := fnv.New64a()
+	fmt.Fprint(h, "rebid worker: ", job)
+	return int64(h.Sum64() & 0x7fffffffffffffff)
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"rebid/internal/services"
+	"time"
+)
+
+const dispatcherName = "notification dispatcher"
+
+// StartNotificationDispatcher delivers queued notifications every interval
+// until d is cancelled. Unlike the cron jobs it runs on a plain ticker, as
+// it is meant to poll the outbox every few seconds. Only the node leading
+// the dispatcher delivers.
+func StartNotificationDispatcher(
+	d context.Context,
+	elector *Elector,
+	interval time.Duration,
+	notifySvc *services.NotificationService,
+) {
+	log.Printf("%s: started (interval=%s)", dispatcherName, interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.Done():
+				log.Printf("%s: stopped", dispatcherName)
+				return
+			case <-ticker.C:
+				if elector.Lead(d, dispatcherName) {
+					RunDispatch(d, notifySvc)
+				}
+			}
+		}
+	}()
+}
+
+// RunDispatch is one pass of the dispatcher: it delivers every notification
+// that is due, scheduling retries for the ones that fail.
+func RunDispatch(ctx context.Context, notifySvc *services.NotificationService) int {
+	delivered, err := notifySvc.Dispatch(ctx)
+	if err != nil {
+		log.Printf("%s: error dispatching notifications: %v", dispatcherName, err)
+	}
+	if delivered > 0 {
+		log.Printf("%s: delivered %d notification(s)", dispatcherName, delivered)
+	}
+	return delivered
+}
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"rebid/internal/models"
+	"rebid/internal/services"
+)
+
+const secondChanceName = "second chance"
+
+func StartSecondChanceOffers(
+	d context.Context,
+	elector *Elector,
+	cronExpr string,
+	offerSvc *services.SecondChanceService,
+) {
+	schedule(d, elector, secondChanceName, cronExpr, func() {
+		RunSecondChance(context.Background(), offerSvc)
+	})
+}
+
+// RunSecondChance is one pass of the second-chance worker: it expires
+// unanswered offers and offers every item whose winner missed the payment
+// deadline to the runner-up.
+func RunSecondChance(ctx context.Context, offerSvc *services.SecondChanceService) []models.SecondChanceOffer {
+	offers, err := offerSvc.OfferLapsed(ctx)
+	if err != nil {
+		log.Printf("%s: error offering lapsed auctions: %v", secondChanceName, err)
+		return nil
+	}
+	if len(offers) > 0 {
+		log.Printf("%s: made %d offer(s)", secondChanceName, len(offers))
+	}
+	return offers
+}
@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"rebid/internal/services"
+)
+
+const reminderName = "watchlist reminder"
+
+func StartWatchlistReminders(
+	d context.Context,
+	elector *Elector,
+	cronExpr string,
+	watchSvc *services.WatchlistService,
+) {
+	schedule(d, elector, reminderName, cronExpr, func() {
+		RunReminders(context.Background(), watchSvc)
+	})
+}
+
+// RunReminders is one pass of the reminder job: it notifies the watchers of
+// every auction that crossed a reminder threshold before its end_time since
+// the last pass.
+func RunReminders(ctx context.Context, watchSvc *services.WatchlistService) int {
+	sent, err := watchSvc.SendReminders(ctx)
+	if err != nil {
+		log.Printf("%s: error sending reminders: %v", reminderName, err)
+		return 0
+	}
+	if sent > 0 {
+		log.Printf("%s: sent %d reminder(s)", reminderName, sent)
+	}
+	return sent
+}
@@ -5,6 +5,7 @@ import "net/http"
 type AppError struct {
 	Message    string
 	StatusCode int
+	Data       interface{}
 }
 
 func (e *AppError) Error() string {
@@ -18,9 +19,19 @@ func NewError(message string, statusCode int) *AppError {
 	}
 }
 
+func NewErrorWithData(message string, statusCode int, data interface{}) *AppError {
+	return &AppError{
+		Message:    message,
+		StatusCode: statusCode,
+		Data:       data,
+	}
+}
+
 func HandleServiceError(w http.ResponseWriter, err error) {
 	if appErr, ok := err.(*AppError); ok {
-		JSONResponse(w, appErr.StatusCode, ErrorResponse(appErr.Message))
+		response := ErrorResponse(appErr.Message)
+		response.Data = appErr.Data
+		JSONResponse(w, appErr.StatusCode, response)
 	} else {
 		JSONResponse(w, http.StatusInternalServerError, ErrorResponse(err.Error()))
 	}
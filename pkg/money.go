@@ -0,0 +1,8 @@
+package pkg
+
+import "math"
+
+// RoundPrice rounds an amount to cents, matching the DECIMAL(10, 2) columns.
+func RoundPrice(v float64) float64 {
+	return math.Round(v*100) / 100
+}